@@ -0,0 +1,238 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/big"
+)
+
+// Elementary functions on big.Float. Each works at a few guard bits above
+// the requested precision and rounds the result to it.
+
+const bigGuardBits = 64
+
+func newBig(prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec)
+}
+
+func bigInt(n int64, prec uint) *big.Float {
+	return newBig(prec).SetInt64(n)
+}
+
+// bigExponent returns the binary exponent of x, zero for zero.
+func bigExponent(x *big.Float) int {
+	return x.MantExp(nil)
+}
+
+// bigGuard returns extra working bits for range reduction of x, which
+// cancels about as many leading bits as x has above 1.
+func bigGuard(x *big.Float) uint {
+	if e := bigExponent(x); e > 0 {
+		return uint(e)
+	}
+
+	return 0
+}
+
+// bigSeries sums term(n) until the terms drop below 2^-prec relative to
+// one.
+func bigSeries(prec uint, term func(n int) *big.Float) *big.Float {
+	sum := newBig(prec)
+	for n := 0; ; n++ {
+		t := term(n)
+		sum.Add(sum, t)
+		if t.Sign() == 0 || bigExponent(t) < -int(prec) {
+			return sum
+		}
+	}
+}
+
+// bigAtanh returns atanh(t) for |t| well below one.
+func bigAtanh(t *big.Float, prec uint) *big.Float {
+	square := newBig(prec).Mul(t, t)
+	power := newBig(prec).Set(t)
+
+	return bigSeries(prec, func(n int) *big.Float {
+		term := newBig(prec).Quo(power, bigInt(int64(2*n+1), prec))
+		power.Mul(power, square)
+		return term
+	})
+}
+
+// bigAtanSmall returns atan(t) by its Taylor series for |t| well below one.
+func bigAtanSmall(t *big.Float, prec uint) *big.Float {
+	square := newBig(prec).Mul(t, t)
+	square.Neg(square)
+	power := newBig(prec).Set(t)
+
+	return bigSeries(prec, func(n int) *big.Float {
+		term := newBig(prec).Quo(power, bigInt(int64(2*n+1), prec))
+		power.Mul(power, square)
+		return term
+	})
+}
+
+func bigLn2(prec uint) *big.Float {
+	wp := prec + bigGuardBits
+	third := newBig(wp).Quo(bigInt(1, wp), bigInt(3, wp))
+	ln2 := bigAtanh(third, wp)
+
+	return newBig(prec).Mul(ln2, bigInt(2, wp))
+}
+
+// bigPi uses Machin's formula π = 16 atan(1/5) - 4 atan(1/239).
+func bigPi(prec uint) *big.Float {
+	wp := prec + bigGuardBits
+	a := bigAtanSmall(newBig(wp).Quo(bigInt(1, wp), bigInt(5, wp)), wp)
+	b := bigAtanSmall(newBig(wp).Quo(bigInt(1, wp), bigInt(239, wp)), wp)
+	a.Mul(a, bigInt(16, wp))
+	b.Mul(b, bigInt(4, wp))
+
+	return newBig(prec).Sub(a, b)
+}
+
+func bigExp(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return bigInt(1, prec)
+	}
+
+	// x = k ln2 + r with |r| <= ln2/2, then r is scaled by 2^-halvings so
+	// the series converges fast, and the sum is squared back.
+	const halvings = 16
+	wp := prec + bigGuardBits + halvings + bigGuard(x)
+	ln2 := bigLn2(wp)
+
+	kf, _ := newBig(wp).Quo(x, ln2).Float64()
+	k := math.Round(kf)
+	r := newBig(wp).Mul(ln2, newBig(wp).SetFloat64(k))
+	r.Sub(x, r)
+	r.SetMantExp(r, -halvings)
+
+	term := bigInt(1, wp)
+	sum := bigSeries(wp, func(n int) *big.Float {
+		if n > 0 {
+			term.Mul(term, r)
+			term.Quo(term, bigInt(int64(n), wp))
+		}
+		return newBig(wp).Set(term)
+	})
+	for n := 0; n < halvings; n++ {
+		sum.Mul(sum, sum)
+	}
+
+	return newBig(prec).SetMantExp(sum, int(k))
+}
+
+// bigLog returns ln x for x > 0.
+func bigLog(x *big.Float, prec uint) *big.Float {
+	wp := prec + bigGuardBits
+
+	// x = m 2^e with m in [√½, √2), so that (m-1)/(m+1) is below 0.18.
+	m := newBig(wp)
+	e := x.MantExp(m)
+	if m.Cmp(big.NewFloat(math.Sqrt2/2)) < 0 {
+		m.SetMantExp(m, 1)
+		e--
+	}
+
+	t := newBig(wp).Quo(newBig(wp).Sub(m, bigInt(1, wp)), newBig(wp).Add(m, bigInt(1, wp)))
+	lnM := bigAtanh(t, wp)
+	lnM.Mul(lnM, bigInt(2, wp))
+
+	res := newBig(wp).Mul(bigLn2(wp), bigInt(int64(e), wp))
+
+	return newBig(prec).Add(res, lnM)
+}
+
+// bigSinCos returns sin x and cos x.
+func bigSinCos(x *big.Float, prec uint) (*big.Float, *big.Float) {
+	wp := prec + bigGuardBits + bigGuard(x)
+	halfPi := bigPi(wp)
+	halfPi.SetMantExp(halfPi, -1)
+
+	nf, _ := newBig(wp).Quo(x, halfPi).Float64()
+	n := math.Round(nf)
+	r := newBig(wp).Mul(halfPi, newBig(wp).SetFloat64(n))
+	r.Sub(x, r)
+
+	square := newBig(wp).Mul(r, r)
+	square.Neg(square)
+
+	term := newBig(wp).Set(r)
+	sin := bigSeries(wp, func(k int) *big.Float {
+		if k > 0 {
+			term.Mul(term, square)
+			term.Quo(term, bigInt(int64((2*k)*(2*k+1)), wp))
+		}
+		return newBig(wp).Set(term)
+	})
+
+	term = bigInt(1, wp)
+	cos := bigSeries(wp, func(k int) *big.Float {
+		if k > 0 {
+			term.Mul(term, square)
+			term.Quo(term, bigInt(int64((2*k-1)*(2*k)), wp))
+		}
+		return newBig(wp).Set(term)
+	})
+
+	switch int64(math.Mod(n, 4)+4) % 4 {
+	case 1:
+		sin, cos = cos, sin.Neg(sin)
+	case 2:
+		sin, cos = sin.Neg(sin), cos.Neg(cos)
+	case 3:
+		sin, cos = cos.Neg(cos), sin
+	}
+
+	return newBig(prec).Set(sin), newBig(prec).Set(cos)
+}
+
+// bigAtan2 returns the angle of (x, y) in [-π, π].
+func bigAtan2(y, x *big.Float, prec uint) *big.Float {
+	wp := prec + bigGuardBits
+
+	if x.Sign() == 0 {
+		if y.Sign() == 0 {
+			return newBig(prec)
+		}
+		halfPi := bigPi(wp)
+		halfPi.SetMantExp(halfPi, -1)
+		if y.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return newBig(prec).Set(halfPi)
+	}
+
+	// Reduce |t| below one, then halve the angle with
+	// atan t = 2 atan(t / (1 + √(1 + t²))) until the series is quick.
+	t := newBig(wp).Quo(y, x)
+	t.Abs(t)
+	inverted := t.Cmp(bigInt(1, wp)) > 0
+	if inverted {
+		t.Quo(bigInt(1, wp), t)
+	}
+	const halvings = 4
+	for n := 0; n < halvings; n++ {
+		root := newBig(wp).Mul(t, t)
+		root.Add(root, bigInt(1, wp))
+		root.Sqrt(root)
+		root.Add(root, bigInt(1, wp))
+		t.Quo(t, root)
+	}
+	angle := bigAtanSmall(t, wp)
+	angle.SetMantExp(angle, halvings)
+
+	pi := bigPi(wp)
+	if inverted {
+		halfPi := newBig(wp).SetMantExp(pi, -1)
+		angle.Sub(halfPi, angle)
+	}
+	if x.Sign() < 0 {
+		angle.Sub(pi, angle)
+	}
+	if y.Sign() < 0 {
+		angle.Neg(angle)
+	}
+
+	return newBig(prec).Set(angle)
+}
@@ -0,0 +1,244 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigQuaternion is a quaternion with big.Float components. Operations round
+// to the precision of the receiver, in bits.
+type BigQuaternion struct {
+	W, I, J, K *big.Float
+	prec       uint
+}
+
+// NewBigQuaternionByCoords copies the components, rounding them to prec.
+func NewBigQuaternionByCoords(prec uint, W, I, J, K *big.Float) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(prec).Set(W),
+		I:    newBig(prec).Set(I),
+		J:    newBig(prec).Set(J),
+		K:    newBig(prec).Set(K),
+		prec: prec,
+	}
+}
+
+func NewBigQuaternionFromFloat(q *Quaternion, prec uint) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(prec).SetFloat64(q.W),
+		I:    newBig(prec).SetFloat64(q.I),
+		J:    newBig(prec).SetFloat64(q.J),
+		K:    newBig(prec).SetFloat64(q.K),
+		prec: prec,
+	}
+}
+
+func (q *BigQuaternion) Prec() uint {
+	return q.prec
+}
+
+// Float returns the nearest Quaternion.
+func (q *BigQuaternion) Float() *Quaternion {
+	w, _ := q.W.Float64()
+	i, _ := q.I.Float64()
+	j, _ := q.J.Float64()
+	k, _ := q.K.Float64()
+
+	return NewQuaternionByCoords(w, i, j, k)
+}
+
+func (q *BigQuaternion) Add(arg *BigQuaternion) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(q.prec).Add(q.W, arg.W),
+		I:    newBig(q.prec).Add(q.I, arg.I),
+		J:    newBig(q.prec).Add(q.J, arg.J),
+		K:    newBig(q.prec).Add(q.K, arg.K),
+		prec: q.prec,
+	}
+}
+
+func (q *BigQuaternion) Sub(arg *BigQuaternion) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(q.prec).Sub(q.W, arg.W),
+		I:    newBig(q.prec).Sub(q.I, arg.I),
+		J:    newBig(q.prec).Sub(q.J, arg.J),
+		K:    newBig(q.prec).Sub(q.K, arg.K),
+		prec: q.prec,
+	}
+}
+
+func (q *BigQuaternion) MulByGrassmann(arg *BigQuaternion) *BigQuaternion {
+	// Products are exact at twice the precision, so only the sums round.
+	wp := 2*q.prec + bigGuardBits
+	sum := func(a ...*big.Float) *big.Float {
+		s := newBig(wp).Mul(a[0], a[1])
+		for n := 2; n < len(a); n += 2 {
+			s.Add(s, newBig(wp).Mul(a[n], a[n+1]))
+		}
+		return newBig(q.prec).Set(s)
+	}
+	neg := func(x *big.Float) *big.Float {
+		return newBig(wp).Neg(x)
+	}
+
+	return &BigQuaternion{
+		W:    sum(q.W, arg.W, neg(q.I), arg.I, neg(q.J), arg.J, neg(q.K), arg.K),
+		I:    sum(q.W, arg.I, q.I, arg.W, q.J, arg.K, neg(q.K), arg.J),
+		J:    sum(q.W, arg.J, neg(q.I), arg.K, q.J, arg.W, q.K, arg.I),
+		K:    sum(q.W, arg.K, q.I, arg.J, neg(q.J), arg.I, q.K, arg.W),
+		prec: q.prec,
+	}
+}
+
+func (q *BigQuaternion) MulByNumber(n *big.Float) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(q.prec).Mul(q.W, n),
+		I:    newBig(q.prec).Mul(q.I, n),
+		J:    newBig(q.prec).Mul(q.J, n),
+		K:    newBig(q.prec).Mul(q.K, n),
+		prec: q.prec,
+	}
+}
+
+func (q *BigQuaternion) Conjugate() *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(q.prec).Set(q.W),
+		I:    newBig(q.prec).Neg(q.I),
+		J:    newBig(q.prec).Neg(q.J),
+		K:    newBig(q.prec).Neg(q.K),
+		prec: q.prec,
+	}
+}
+
+// Norm returns the squared length, as Quaternion.Norm does.
+func (q *BigQuaternion) Norm() *big.Float {
+	return newBig(q.prec).Set(q.norm(q.prec + bigGuardBits))
+}
+
+func (q *BigQuaternion) norm(prec uint) *big.Float {
+	s := newBig(prec)
+	for _, c := range [4]*big.Float{q.W, q.I, q.J, q.K} {
+		s.Add(s, newBig(prec).Mul(c, c))
+	}
+
+	return s
+}
+
+// vectorLength returns the length of the vector part.
+func (q *BigQuaternion) vectorLength(prec uint) *big.Float {
+	s := newBig(prec)
+	for _, c := range [3]*big.Float{q.I, q.J, q.K} {
+		s.Add(s, newBig(prec).Mul(c, c))
+	}
+
+	return s.Sqrt(s)
+}
+
+func (q *BigQuaternion) Reverse() (*BigQuaternion, error) {
+	norm := q.norm(q.prec + bigGuardBits)
+	if norm.Sign() == 0 {
+		return nil, AllComponentsEqualsToZeroError
+	}
+
+	return q.Conjugate().MulByNumber(norm.Quo(bigInt(1, norm.Prec()), norm)), nil
+}
+
+func (q *BigQuaternion) Normalize() (*BigQuaternion, error) {
+	norm := q.norm(q.prec + bigGuardBits)
+	if norm.Sign() == 0 {
+		return nil, AllComponentsEqualsToZeroError
+	}
+
+	return q.MulByNumber(norm.Quo(bigInt(1, norm.Prec()), norm.Sqrt(norm))), nil
+}
+
+// Exp returns e^w (cos|v| + v/|v| sin|v|) for q = w + v.
+func (q *BigQuaternion) Exp() *BigQuaternion {
+	wp := q.prec + bigGuardBits
+	scale := bigExp(q.W, wp)
+
+	angle := q.vectorLength(wp)
+	sin, cos := bigSinCos(angle, wp)
+
+	// sin|v|/|v| tends to one for a vanishing vector part.
+	factor := bigInt(1, wp)
+	if angle.Sign() != 0 {
+		factor.Quo(sin, angle)
+	}
+	factor.Mul(factor, scale)
+
+	return q.withVector(newBig(wp).Mul(scale, cos), factor)
+}
+
+// Log returns ln|q| + v/|v| atan2(|v|, w). For a negative real q the vector
+// part is taken along I.
+func (q *BigQuaternion) Log() (*BigQuaternion, error) {
+	wp := q.prec + bigGuardBits
+	norm := q.norm(wp)
+	if norm.Sign() == 0 {
+		return nil, AllComponentsEqualsToZeroError
+	}
+
+	scalar := bigLog(norm, wp)
+	scalar.SetMantExp(scalar, -1)
+
+	length := q.vectorLength(wp)
+	angle := bigAtan2(length, q.W, wp)
+	if length.Sign() == 0 {
+		res := q.withVector(scalar, newBig(wp))
+		res.I.Set(angle)
+		return res, nil
+	}
+
+	return q.withVector(scalar, angle.Quo(angle, length)), nil
+}
+
+// Sqrt returns the principal square root, whose scalar part is not
+// negative. The square root of a negative real q lies along I.
+func (q *BigQuaternion) Sqrt() *BigQuaternion {
+	wp := q.prec + bigGuardBits
+	length := q.norm(wp)
+	length.Sqrt(length)
+	if length.Sign() == 0 {
+		return q.withVector(newBig(wp), newBig(wp))
+	}
+
+	// √q = √((|q| + w)/2) + v/|v| √((|q| - w)/2), written so that neither
+	// part cancels: the smaller one comes from |v| / (2 √((|q| + w)/2)).
+	half := func(x *big.Float) *big.Float {
+		return x.SetMantExp(x, -1)
+	}
+	vector := q.vectorLength(wp)
+
+	if q.W.Sign() >= 0 {
+		scalar := half(newBig(wp).Add(length, q.W))
+		scalar.Sqrt(scalar)
+		return q.withVector(scalar, half(newBig(wp).Quo(bigInt(1, wp), scalar)))
+	}
+
+	imaginary := half(newBig(wp).Sub(length, q.W))
+	imaginary.Sqrt(imaginary)
+	if vector.Sign() == 0 {
+		res := q.withVector(newBig(wp), newBig(wp))
+		res.I.Set(imaginary)
+		return res
+	}
+	scalar := half(newBig(wp).Quo(vector, imaginary))
+
+	return q.withVector(scalar, imaginary.Quo(imaginary, vector))
+}
+
+// withVector returns scalar + factor*v, where v is the vector part of q.
+func (q *BigQuaternion) withVector(scalar, factor *big.Float) *BigQuaternion {
+	return &BigQuaternion{
+		W:    newBig(q.prec).Set(scalar),
+		I:    newBig(q.prec).Mul(q.I, factor),
+		J:    newBig(q.prec).Mul(q.J, factor),
+		K:    newBig(q.prec).Mul(q.K, factor),
+		prec: q.prec,
+	}
+}
+
+func (q *BigQuaternion) String() string {
+	return fmt.Sprintf("(%v)+(%v)i+(%v)j+(%v)k", q.W, q.I, q.J, q.K)
+}
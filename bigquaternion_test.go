@@ -0,0 +1,192 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+const bigTestPrec = 256
+
+// bigClose reports whether a and b agree to about prec - 16 bits relative
+// to max(1, |b|).
+func bigClose(a, b *big.Float, prec uint) bool {
+	d := newBig(prec).Sub(a, b)
+	if d.Sign() == 0 {
+		return true
+	}
+	scale := 0
+	if b.Sign() != 0 && bigExponent(b) > 0 {
+		scale = bigExponent(b)
+	}
+
+	return bigExponent(d)-scale < -int(prec)+16
+}
+
+func bigQuaternionClose(a, b *BigQuaternion) bool {
+	return bigClose(a.W, b.W, a.prec) && bigClose(a.I, b.I, a.prec) && bigClose(a.J, b.J, a.prec) && bigClose(a.K, b.K, a.prec)
+}
+
+func randomBigQuaternion(scale float64) *BigQuaternion {
+	return NewBigQuaternionFromFloat(NewQuaternionByCoords(
+		(rand.Float64()*2-1)*scale, (rand.Float64()*2-1)*scale, (rand.Float64()*2-1)*scale, (rand.Float64()*2-1)*scale,
+	), bigTestPrec)
+}
+
+func TestBigMath_Constants(t *testing.T) {
+	tests := []struct {
+		name string
+		got  *big.Float
+		want string
+	}{
+		{name: "pi", got: bigPi(bigTestPrec), want: "3.14159265358979323846264338327950288419716939937510582097494"},
+		{name: "ln2", got: bigLn2(bigTestPrec), want: "0.693147180559945309417232121458176568075500134360255254120680"},
+		{name: "e", got: bigExp(bigInt(1, bigTestPrec), bigTestPrec), want: "2.71828182845904523536028747135266249775724709369995957496697"},
+		{name: "ln10", got: bigLog(bigInt(10, bigTestPrec), bigTestPrec), want: "2.30258509299404568401799145468436420760110148862877297603333"},
+		{name: "sin1", got: func() *big.Float { s, _ := bigSinCos(bigInt(1, bigTestPrec), bigTestPrec); return s }(), want: "0.841470984807896506652502321630298999622563060798371065672752"},
+		{name: "atan1", got: bigAtan2(bigInt(1, bigTestPrec), bigInt(1, bigTestPrec), bigTestPrec), want: "0.785398163397448309615660845819875721049292349843776455243736"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, _, _ := big.ParseFloat(tt.want, 10, bigTestPrec, big.ToNearestEven)
+			// The expected values carry 60 digits, about 199 bits.
+			if d := newBig(bigTestPrec).Sub(tt.got, want); d.Sign() != 0 && bigExponent(d) > -195 {
+				t.Errorf("Wrong value of %s. Expected %v, got %v", tt.name, tt.want, tt.got.Text('g', 60))
+			}
+		})
+	}
+}
+
+func TestBigMath_ShouldMatchFloat64(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		x := (rand.Float64()*2 - 1) * 50
+		y := (rand.Float64()*2 - 1) * 50
+		bx, by := newBig(bigTestPrec).SetFloat64(x), newBig(bigTestPrec).SetFloat64(y)
+
+		sin, cos := bigSinCos(bx, bigTestPrec)
+		exp := bigExp(bx, bigTestPrec)
+		log := bigLog(newBig(bigTestPrec).Abs(bx), bigTestPrec)
+		atan := bigAtan2(by, bx, bigTestPrec)
+
+		for _, c := range []struct {
+			name      string
+			got, want float64
+		}{
+			{name: "sin", want: math.Sin(x)},
+			{name: "cos", want: math.Cos(x)},
+			{name: "exp", want: math.Exp(x)},
+			{name: "log", want: math.Log(math.Abs(x))},
+			{name: "atan2", want: math.Atan2(y, x)},
+		} {
+			switch c.name {
+			case "sin":
+				c.got, _ = sin.Float64()
+			case "cos":
+				c.got, _ = cos.Float64()
+			case "exp":
+				c.got, _ = exp.Float64()
+			case "log":
+				c.got, _ = log.Float64()
+			case "atan2":
+				c.got, _ = atan.Float64()
+			}
+			if math.Abs(c.got-c.want) > 4e-16*math.Max(1, math.Abs(c.want)) {
+				t.Errorf("Wrong %s(%v). Expected %v, got %v", c.name, x, c.want, c.got)
+			}
+		}
+	}
+}
+
+func TestBigQuaternion_ExpLog(t *testing.T) {
+	for n := 0; n < 20; n++ {
+		q := randomBigQuaternion(3)
+
+		log, err := q.Log()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := log.Exp(); !bigQuaternionClose(got, q) {
+			t.Errorf("Wrong result of Exp(Log(%v)). Got %v", q, got)
+		}
+
+		// Exp(Log(q)/2) is the principal square root.
+		root := q.Sqrt()
+		if got := root.MulByGrassmann(root); !bigQuaternionClose(got, q) {
+			t.Errorf("Wrong result of Sqrt(%v)². Got %v", q, got)
+		}
+		if half := log.MulByNumber(big.NewFloat(0.5)).Exp(); !bigQuaternionClose(half, root) {
+			t.Errorf("Wrong result of Sqrt(%v). Expected %v, got %v", q, half, root)
+		}
+	}
+}
+
+func TestBigQuaternion_ShouldMatchQuaternion(t *testing.T) {
+	a := NewQuaternionByCoords(rand.Float64(), -rand.Float64(), rand.Float64(), 0.5)
+	b := NewQuaternionByCoords(-rand.Float64(), rand.Float64(), 2, -rand.Float64())
+	ba, bb := NewBigQuaternionFromFloat(a, bigTestPrec), NewBigQuaternionFromFloat(b, bigTestPrec)
+
+	if got := ba.Float(); *got != *a {
+		t.Errorf("Wrong result of float round trip. Expected %v, got %v", a, got)
+	}
+	if got, want := ba.MulByGrassmann(bb).Float(), a.MulByGrassmann(b); !got.equalsByCoords(want, 1e-15) {
+		t.Errorf("Wrong result of mul. Expected %v, got %v", want, got)
+	}
+
+	// Exact rational arithmetic agrees with the big.Float product to the
+	// last bit, since the components have few significant bits.
+	ra, _ := NewRatQuaternionFromFloat(a)
+	rb, _ := NewRatQuaternionFromFloat(b)
+	want := ra.MulByGrassmann(rb)
+	got := ba.MulByGrassmann(bb)
+	for n, c := range [4]*big.Float{got.W, got.I, got.J, got.K} {
+		r, _ := c.Rat(nil)
+		if r.Cmp([4]*big.Rat{want.W, want.I, want.J, want.K}[n]) != 0 {
+			t.Errorf("Wrong component %d of product. Expected %v, got %v", n, want, got)
+		}
+	}
+
+	unit, err := ba.Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bigClose(unit.Norm(), bigInt(1, bigTestPrec), bigTestPrec) {
+		t.Errorf("Wrong norm after Normalize. Expected 1, got %v", unit.Norm())
+	}
+
+	reverse, err := ba.Reverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := NewBigQuaternionFromFloat(NewQuaternionByCoords(1, 0, 0, 0), bigTestPrec)
+	if got := ba.MulByGrassmann(reverse); !bigQuaternionClose(got, one) {
+		t.Errorf("Wrong result of mul by reverse. Expected 1, got %v", got)
+	}
+}
+
+func TestBigQuaternion_RealValues(t *testing.T) {
+	minusFour := NewBigQuaternionFromFloat(NewQuaternionByCoords(-4, 0, 0, 0), bigTestPrec)
+
+	if got := minusFour.Sqrt().Float(); *got != *NewQuaternionByCoords(0, 2, 0, 0) {
+		t.Errorf("Wrong result of Sqrt(-4). Expected 2i, got %v", got)
+	}
+
+	log, err := minusFour.Log()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := log.Float(); !got.Equals(NewQuaternionByCoords(math.Log(4), math.Pi, 0, 0), 1e-15) {
+		t.Errorf("Wrong result of Log(-4). Expected ln4 + πi, got %v", got)
+	}
+
+	zero := NewBigQuaternionFromFloat(NewQuaternionByCoords(0, 0, 0, 0), bigTestPrec)
+	if _, err := zero.Log(); err == nil {
+		t.Errorf("Expected error for Log(0)")
+	}
+	if _, err := zero.Normalize(); err == nil {
+		t.Errorf("Expected error for normalizing zero")
+	}
+	if got := zero.Exp().Float(); *got != *NewQuaternionByCoords(1, 0, 0, 0) {
+		t.Errorf("Wrong result of Exp(0). Expected 1, got %v", got)
+	}
+}
@@ -14,6 +14,15 @@ func NewBQuaternion(p *Quaternion, q *Quaternion) *BQuaternion {
 	}
 }
 
+// NewRigidBQuaternion builds the motion that rotates a point and then moves
+// it by translation, as in Vec3.RotateAndStepTo.
+func NewRigidBQuaternion(rotation *Quaternion, translation *Vec3) *BQuaternion {
+	return NewBQuaternion(
+		rotation,
+		NewQuaternionByCoords(0, translation.X/2, translation.Y/2, translation.Z/2).MulByGrassmann(rotation),
+	)
+}
+
 func (bq *BQuaternion) Add(arg *BQuaternion) *BQuaternion {
 	return NewBQuaternion(
 		bq.P.Add(arg.P),
@@ -49,6 +58,22 @@ func (bq *BQuaternion) ComplexConjugate() *BQuaternion {
 	)
 }
 
+func (bq *BQuaternion) Rotation() *Quaternion {
+	return NewQuaternionByCoords(bq.P.W, bq.P.I, bq.P.J, bq.P.K)
+}
+
+// Translation is the vector part of 2*Q*P', the offset applied after the
+// rotation when P is a unit quaternion.
+func (bq *BQuaternion) Translation() *Vec3 {
+	t := bq.Q.MulByGrassmann(bq.P.Conjugate())
+
+	return &Vec3{
+		X: 2 * t.I,
+		Y: 2 * t.J,
+		Z: 2 * t.K,
+	}
+}
+
 func (bq *BQuaternion) Equals(arg *BQuaternion, eps float64) bool {
 	return bq.P.Equals(arg.P, eps) && bq.Q.Equals(arg.Q, eps)
 }
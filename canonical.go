@@ -0,0 +1,71 @@
+package go_quaternions
+
+import "math"
+
+// RotationKey is a quantized canonical rotation, comparable and usable as a
+// map key. Equal rotations always share a key, including q and -q, but two
+// rotations closer than the resolution may still fall on either side of a
+// cell boundary and get keys that differ by one in some component.
+type RotationKey struct {
+	W, I, J, K int64
+}
+
+// Canonical returns q or -q so that the first non-zero component of
+// W, I, J, K is positive. Both quaternions describe the same rotation.
+func (q *Quaternion) Canonical() *Quaternion {
+	for _, c := range [4]float64{q.W, q.I, q.J, q.K} {
+		if c < 0 {
+			return q.MulByNumber(-1)
+		}
+		if c > 0 {
+			break
+		}
+	}
+
+	return NewQuaternionByCoords(q.W, q.I, q.J, q.K)
+}
+
+// EqualsRotation reports whether q and arg describe the same rotation, i.e.
+// their normalized forms are equal up to sign.
+func (q *Quaternion) EqualsRotation(arg *Quaternion, eps float64) bool {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return false
+	}
+	normArg, err := arg.Normalize()
+	if err != nil {
+		return false
+	}
+
+	return normQ.equalsByCoords(normArg, eps) || normQ.equalsByCoords(normArg.MulByNumber(-1), eps)
+}
+
+// RotationKey quantizes the normalized q and then applies the sign rule of
+// Canonical to the quantized components. Choosing the sign after rounding
+// keeps rotations near a half turn, whose W rounds to zero, on one key
+// whatever the sign of the tiny W.
+func (q *Quaternion) RotationKey(resolution float64) RotationKey {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return RotationKey{}
+	}
+
+	key := [4]int64{
+		int64(math.Round(normQ.W / resolution)),
+		int64(math.Round(normQ.I / resolution)),
+		int64(math.Round(normQ.J / resolution)),
+		int64(math.Round(normQ.K / resolution)),
+	}
+	for _, c := range key {
+		if c < 0 {
+			for n := range key {
+				key[n] = -key[n]
+			}
+		}
+		if c != 0 {
+			break
+		}
+	}
+
+	return RotationKey{W: key[0], I: key[1], J: key[2], K: key[3]}
+}
@@ -0,0 +1,98 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuaternion_Canonical(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Quaternion
+		want *Quaternion
+	}{
+		{
+			name: "positive scalar",
+			q:    NewQuaternionByCoords(0.5, -0.5, 0.5, -0.5),
+			want: NewQuaternionByCoords(0.5, -0.5, 0.5, -0.5),
+		},
+		{
+			name: "negative scalar",
+			q:    NewQuaternionByCoords(-0.5, -0.5, 0.5, -0.5),
+			want: NewQuaternionByCoords(0.5, 0.5, -0.5, 0.5),
+		},
+		{
+			name: "zero scalar",
+			q:    NewQuaternionByCoords(0, 0, -1, 0),
+			want: NewQuaternionByCoords(0, 0, 1, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Canonical(); !got.Equals(tt.want, EqualsEpsilon) {
+				t.Errorf("Wrong canonical form of %v. Expected %v, got %v", tt.q, tt.want, got)
+			}
+			if got := tt.q.MulByNumber(-1).Canonical(); !got.Equals(tt.want, EqualsEpsilon) {
+				t.Errorf("Wrong canonical form of -%v. Expected %v, got %v", tt.q, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuaternion_EqualsRotation(t *testing.T) {
+	q, err := NewQuaternionByCoords(0, rand.Float64(), rand.Float64(), 1).ToRotateQuaternion(rand.Float64() * 2 * math.Pi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Equals(q.MulByNumber(-1), 1e-12) {
+		t.Fatalf("Expected %v to differ from its negation by components", q)
+	}
+	if !q.EqualsRotation(q.MulByNumber(-1), 1e-12) {
+		t.Errorf("Expected %v to be the same rotation as its negation", q)
+	}
+	if !q.EqualsRotation(q.MulByNumber(3), 1e-12) {
+		t.Errorf("Expected %v to be the same rotation as a scaled copy", q)
+	}
+	if q.EqualsRotation(q.MulByGrassmann(NewQuaternionByCoords(0, 1, 0, 0)), 1e-12) {
+		t.Errorf("Expected %v to differ from a rotated copy", q)
+	}
+	if q.EqualsRotation(NewQuaternionByCoords(0, 0, 0, 0), 1e-12) {
+		t.Errorf("Expected %v to differ from zero", q)
+	}
+}
+
+func TestQuaternion_RotationKey(t *testing.T) {
+	seen := map[RotationKey]int{}
+	for n := 0; n < 10; n++ {
+		q, err := NewQuaternionByCoords(0, 1, 2, 3).ToRotateQuaternion(0.1 * float64(n+1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen[q.RotationKey(1e-6)]++
+		seen[q.MulByNumber(-2).RotationKey(1e-6)]++
+	}
+
+	if len(seen) != 10 {
+		t.Errorf("Expected 10 distinct rotations, got %v", len(seen))
+	}
+	for key, count := range seen {
+		if count != 2 {
+			t.Errorf("Expected key %v to be shared by q and -q, got %v", key, count)
+		}
+	}
+}
+
+func TestQuaternion_RotationKey_ShouldIgnoreSignOfRoundedW(t *testing.T) {
+	a := NewQuaternionByCoords(1e-12, 1, 0, 0).RotationKey(1e-3)
+	b := NewQuaternionByCoords(-1e-12, 1, 0, 0).RotationKey(1e-3)
+
+	if a != b {
+		t.Errorf("Wrong key near a half turn. Expected %v, got %v", a, b)
+	}
+	if want := (RotationKey{I: 1000}); a != want {
+		t.Errorf("Wrong key near a half turn. Expected %v, got %v", want, a)
+	}
+}
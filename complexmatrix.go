@@ -0,0 +1,297 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	eigenIterations   = 60
+	inverseIterations = 3
+)
+
+func newComplexMatrix(rows, cols int) [][]complex128 {
+	m := make([][]complex128, rows)
+	for r := range m {
+		m[r] = make([]complex128, cols)
+	}
+
+	return m
+}
+
+func conj(z complex128) complex128 {
+	return cmplx.Conj(z)
+}
+
+// complexInverse inverts a by Gauss-Jordan elimination with partial
+// pivoting. A pivot below the rounding error of the matrix counts as zero.
+func complexInverse(a [][]complex128) ([][]complex128, error) {
+	n := len(a)
+	work := newComplexMatrix(n, 2*n)
+	var scale float64
+	for r := range a {
+		copy(work[r], a[r])
+		work[r][n+r] = 1
+		for _, z := range a[r] {
+			scale = math.Max(scale, cmplx.Abs(z))
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if cmplx.Abs(work[r][col]) > cmplx.Abs(work[pivot][col]) {
+				pivot = r
+			}
+		}
+		if cmplx.Abs(work[pivot][col]) <= float64(n)*epsilon*scale {
+			return nil, SingularMatrixError
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		f := 1 / work[col][col]
+		for c := range work[col] {
+			work[col][c] *= f
+		}
+		for r := 0; r < n; r++ {
+			if r == col || work[r][col] == 0 {
+				continue
+			}
+			f := work[r][col]
+			for c := range work[r] {
+				work[r][c] -= f * work[col][c]
+			}
+		}
+	}
+
+	inverse := newComplexMatrix(n, n)
+	for r := range inverse {
+		copy(inverse[r], work[r][n:])
+	}
+
+	return inverse, nil
+}
+
+// complexEigenvalues returns the eigenvalues of a square matrix by reduction
+// to Hessenberg form and the shifted QR algorithm with Givens rotations.
+func complexEigenvalues(a [][]complex128) []complex128 {
+	n := len(a)
+	h := newComplexMatrix(n, n)
+	for r := range a {
+		copy(h[r], a[r])
+	}
+	hessenberg(h)
+
+	values := make([]complex128, n)
+	hi := n - 1
+	for iteration := 0; hi >= 0; iteration++ {
+		// Find the start of the trailing unreduced block.
+		lo := hi
+		for lo > 0 {
+			if cmplx.Abs(h[lo][lo-1]) <= epsilon*(cmplx.Abs(h[lo-1][lo-1])+cmplx.Abs(h[lo][lo])) {
+				h[lo][lo-1] = 0
+				break
+			}
+			lo--
+		}
+		if lo == hi || iteration == eigenIterations*n {
+			values[hi] = h[hi][hi]
+			hi--
+			iteration = 0
+			continue
+		}
+
+		shift := wilkinsonShift(h[hi-1][hi-1], h[hi-1][hi], h[hi][hi-1], h[hi][hi])
+		if iteration%10 == 9 {
+			// An exceptional shift breaks cycles.
+			shift += complex(cmplx.Abs(h[hi][hi-1]), 0)
+		}
+		qrStep(h, lo, hi, shift)
+	}
+
+	return values
+}
+
+// hessenberg reduces h in place by Householder reflections.
+func hessenberg(h [][]complex128) {
+	n := len(h)
+	for k := 0; k < n-2; k++ {
+		var norm float64
+		for r := k + 1; r < n; r++ {
+			norm += real(h[r][k])*real(h[r][k]) + imag(h[r][k])*imag(h[r][k])
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			continue
+		}
+
+		// v = x + e^{iθ}|x|e₁ avoids cancellation in its first entry.
+		v := make([]complex128, n)
+		phase := complex(1, 0)
+		if x := h[k+1][k]; x != 0 {
+			phase = x / complex(cmplx.Abs(x), 0)
+		}
+		for r := k + 1; r < n; r++ {
+			v[r] = h[r][k]
+		}
+		v[k+1] += phase * complex(norm, 0)
+		var vv float64
+		for r := k + 1; r < n; r++ {
+			vv += real(v[r])*real(v[r]) + imag(v[r])*imag(v[r])
+		}
+
+		// H = (I - 2vvᴴ/vᴴv) H (I - 2vvᴴ/vᴴv)
+		for c := 0; c < n; c++ {
+			var s complex128
+			for r := k + 1; r < n; r++ {
+				s += conj(v[r]) * h[r][c]
+			}
+			s *= complex(2/vv, 0)
+			for r := k + 1; r < n; r++ {
+				h[r][c] -= v[r] * s
+			}
+		}
+		for r := 0; r < n; r++ {
+			var s complex128
+			for c := k + 1; c < n; c++ {
+				s += h[r][c] * v[c]
+			}
+			s *= complex(2/vv, 0)
+			for c := k + 1; c < n; c++ {
+				h[r][c] -= s * conj(v[c])
+			}
+		}
+		for r := k + 2; r < n; r++ {
+			h[r][k] = 0
+		}
+	}
+}
+
+// wilkinsonShift returns the eigenvalue of [a b; c d] closer to d.
+func wilkinsonShift(a, b, c, d complex128) complex128 {
+	half := (a - d) / 2
+	root := cmplx.Sqrt(half*half + b*c)
+	if cmplx.Abs(half-root) > cmplx.Abs(half+root) {
+		root = -root
+	}
+
+	if half+root == 0 {
+		return d
+	}
+
+	// d + half - root, without the cancellation.
+	return d - b*c/(half+root)
+}
+
+// qrStep performs one shifted QR step h - μI = QR, h = RQ + μI on the block
+// lo..hi of an upper Hessenberg matrix.
+func qrStep(h [][]complex128, lo, hi int, shift complex128) {
+	for k := lo; k <= hi; k++ {
+		h[k][k] -= shift
+	}
+
+	type rotation struct{ c, s complex128 }
+	rotations := make([]rotation, 0, hi-lo)
+	for k := lo; k < hi; k++ {
+		a, b := h[k][k], h[k+1][k]
+		r := math.Hypot(cmplx.Abs(a), cmplx.Abs(b))
+		if r == 0 {
+			rotations = append(rotations, rotation{c: 1})
+			continue
+		}
+		c, s := a/complex(r, 0), b/complex(r, 0)
+		rotations = append(rotations, rotation{c: c, s: s})
+		for col := k; col <= hi; col++ {
+			x, y := h[k][col], h[k+1][col]
+			h[k][col] = conj(c)*x + conj(s)*y
+			h[k+1][col] = -s*x + c*y
+		}
+	}
+	for n, g := range rotations {
+		k := lo + n
+		top := k + 1
+		if top > hi {
+			top = hi
+		}
+		for row := lo; row <= top; row++ {
+			x, y := h[row][k], h[row][k+1]
+			h[row][k] = x*g.c + y*g.s
+			h[row][k+1] = -x*conj(g.s) + y*conj(g.c)
+		}
+	}
+
+	for k := lo; k <= hi; k++ {
+		h[k][k] += shift
+	}
+}
+
+// complexNullVector returns a unit vector x with a·x ≈ λx by inverse
+// iteration with a slightly perturbed shift. Every iterate is kept
+// orthogonal to the orthonormal vectors of basis, which deflates the
+// eigenvectors already found for a repeated λ.
+func complexNullVector(a [][]complex128, lambda complex128, basis [][]complex128) []complex128 {
+	n := len(a)
+	var scale float64
+	for _, row := range a {
+		for _, z := range row {
+			scale = math.Max(scale, cmplx.Abs(z))
+		}
+	}
+
+	x := make([]complex128, n)
+	for r := range x {
+		x[r] = complex(1, float64(r)/float64(n))
+	}
+	x = orthonormalize(x, basis)
+
+	delta := 1e-12 * math.Max(scale, 1)
+	for attempt := 0; attempt < inverseIterations; {
+		shifted := newComplexMatrix(n, n)
+		for r := range a {
+			copy(shifted[r], a[r])
+			shifted[r][r] -= lambda + complex(delta, 0)
+		}
+		inverse, err := complexInverse(shifted)
+		if err != nil {
+			delta *= 10
+			continue
+		}
+
+		y := make([]complex128, n)
+		for r := range y {
+			for c := range x {
+				y[r] += inverse[r][c] * x[c]
+			}
+		}
+		x = orthonormalize(y, basis)
+		attempt++
+	}
+
+	return x
+}
+
+// orthonormalize removes from x its components along the orthonormal
+// vectors of basis, twice for accuracy, and scales the rest to unit length.
+func orthonormalize(x []complex128, basis [][]complex128) []complex128 {
+	for pass := 0; pass < 2; pass++ {
+		for _, b := range basis {
+			var dot complex128
+			for r := range b {
+				dot += conj(b[r]) * x[r]
+			}
+			for r := range b {
+				x[r] -= dot * b[r]
+			}
+		}
+	}
+
+	var norm float64
+	for _, z := range x {
+		norm = math.Hypot(norm, cmplx.Abs(z))
+	}
+	for r := range x {
+		x[r] /= complex(norm, 0)
+	}
+
+	return x
+}
@@ -0,0 +1,111 @@
+package go_quaternions
+
+// Multiplication selects the sign of the quaternion product: ij = k for
+// Hamilton, ij = -k for JPL. A JPL product p*q equals the Hamilton product
+// q*p.
+type Multiplication int
+
+const (
+	Hamilton Multiplication = iota
+	JPL
+)
+
+// Interpretation selects what a rotation quaternion does to a vector:
+// Active rotates it by the matrix R(q) of the components, while Passive gives
+// its coordinates in the rotated frame, R(q)ᵀv. With the Hamilton product
+// these are v' = q*v*q⁻¹ and q⁻¹*v*q; the JPL product swaps the sandwiches,
+// so that JPL passive is v' = q*v*q⁻¹ as in Trawny & Roumeliotis.
+type Interpretation int
+
+const (
+	Active Interpretation = iota
+	Passive
+)
+
+// Storage selects the order of the components in arrays and files.
+type Storage int
+
+const (
+	ScalarFirst Storage = iota
+	ScalarLast
+)
+
+type Convention struct {
+	Multiplication Multiplication
+	Interpretation Interpretation
+	Storage        Storage
+}
+
+var (
+	// LibraryConvention is the one used by MulByGrassmann, RotateRad and
+	// the rest of this package.
+	LibraryConvention = Convention{Multiplication: Hamilton, Interpretation: Active, Storage: ScalarFirst}
+	// ROSConvention is Hamilton with x, y, z, w storage, as in ROS and
+	// Eigen's coeffs().
+	ROSConvention = Convention{Multiplication: Hamilton, Interpretation: Active, Storage: ScalarLast}
+	// JPLConvention is the passive, scalar last convention of NASA JPL
+	// and Trawny & Roumeliotis.
+	JPLConvention = Convention{Multiplication: JPL, Interpretation: Passive, Storage: ScalarLast}
+)
+
+// Quaternion reads stored components and returns the library quaternion
+// with the same vector map, so that LibraryConvention.Rotate applies what
+// c.Rotate applies with the stored numbers.
+func (c Convention) Quaternion(components [4]float64) *Quaternion {
+	q := NewQuaternionByCoords(components[0], components[1], components[2], components[3])
+	if c.Storage == ScalarLast {
+		q = NewQuaternionByCoords(components[3], components[0], components[1], components[2])
+	}
+
+	return LibraryConvention.Convert(q, c)
+}
+
+// Components is the inverse of Quaternion: it returns the numbers to store
+// in c so that c.Rotate with them maps vectors as LibraryConvention.Rotate
+// maps them with q. The numbers differ from those of q for a passive c.
+func (c Convention) Components(q *Quaternion) [4]float64 {
+	q = c.Convert(q, LibraryConvention)
+	if c.Storage == ScalarLast {
+		return [4]float64{q.I, q.J, q.K, q.W}
+	}
+
+	return [4]float64{q.W, q.I, q.J, q.K}
+}
+
+// Convert returns the quaternion whose vector map under c.Rotate is that of
+// q under from.Rotate; it keeps the map, not the numbers. Storage order does
+// not apply to Quaternion values.
+//
+// Since the vector map depends on the interpretation only, active and
+// passive quaternions are conjugates of each other, whatever the product.
+func (c Convention) Convert(q *Quaternion, from Convention) *Quaternion {
+	if c.conjugated() != from.conjugated() {
+		return q.Conjugate()
+	}
+
+	return NewQuaternionByCoords(q.W, q.I, q.J, q.K)
+}
+
+// Mul returns the product p*q with the multiplication of c.
+func (c Convention) Mul(p, q *Quaternion) *Quaternion {
+	if c.Multiplication == JPL {
+		return q.MulByGrassmann(p)
+	}
+
+	return p.MulByGrassmann(q)
+}
+
+// Rotate applies the unit quaternion q to v as the interpretation of c
+// says: R(q)v when active, R(q)ᵀv when passive. The multiplication of c does
+// not change the result, since each product has its own sandwich, q*v*q⁻¹
+// or q⁻¹*v*q, that gives the same matrix.
+func (c Convention) Rotate(q *Quaternion, v *Vec3) *Vec3 {
+	return LibraryConvention.Convert(q, c).Compile().Apply(v)
+}
+
+// conjugated reports whether the convention stores the conjugate of the
+// Hamilton active quaternion. Only the interpretation matters, since the JPL
+// product also swaps the sides of the sandwich.
+func (c Convention) conjugated() bool {
+	return c.Interpretation == Passive
+}
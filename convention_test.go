@@ -0,0 +1,139 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func allConventions() []Convention {
+	var conventions []Convention
+	for _, m := range []Multiplication{Hamilton, JPL} {
+		for _, i := range []Interpretation{Active, Passive} {
+			for _, s := range []Storage{ScalarFirst, ScalarLast} {
+				conventions = append(conventions, Convention{Multiplication: m, Interpretation: i, Storage: s})
+			}
+		}
+	}
+
+	return conventions
+}
+
+func TestConvention_Rotate_ShouldFollowDefinition(t *testing.T) {
+	q := randomRotation()
+	v := &Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()}
+	pure := NewQuaternionByCoords(0, v.X, v.Y, v.Z)
+
+	r := q.Compile().R
+	for _, c := range allConventions() {
+		// q*v*q⁻¹ in the product of c is active for Hamilton and passive
+		// for JPL.
+		sandwich := c.Mul(c.Mul(q, pure), q.Conjugate())
+		if (c.Multiplication == JPL) != (c.Interpretation == Passive) {
+			sandwich = c.Mul(c.Mul(q.Conjugate(), pure), q)
+		}
+		want := &Vec3{X: sandwich.I, Y: sandwich.J, Z: sandwich.K}
+
+		if got := c.Rotate(q, v); !got.Equals(want, 1e-12) {
+			t.Errorf("Wrong result of Rotate in %+v. Expected %v, got %v", c, want, got)
+		}
+
+		// Active applies R(q), passive its transpose.
+		matrix := &Vec3{
+			X: r[0][0]*v.X + r[0][1]*v.Y + r[0][2]*v.Z,
+			Y: r[1][0]*v.X + r[1][1]*v.Y + r[1][2]*v.Z,
+			Z: r[2][0]*v.X + r[2][1]*v.Y + r[2][2]*v.Z,
+		}
+		if c.Interpretation == Passive {
+			matrix = &Vec3{
+				X: r[0][0]*v.X + r[1][0]*v.Y + r[2][0]*v.Z,
+				Y: r[0][1]*v.X + r[1][1]*v.Y + r[2][1]*v.Z,
+				Z: r[0][2]*v.X + r[1][2]*v.Y + r[2][2]*v.Z,
+			}
+		}
+		if got := c.Rotate(q, v); !got.Equals(matrix, 1e-12) {
+			t.Errorf("Wrong matrix of Rotate in %+v. Expected %v, got %v", c, matrix, got)
+		}
+	}
+}
+
+func TestConvention_QuarterTurn(t *testing.T) {
+	q := NewQuaternionByCoords(math.Sqrt2/2, 0, 0, math.Sqrt2/2)
+	x := &Vec3{X: 1}
+
+	tests := []struct {
+		name       string
+		convention Convention
+		want       *Vec3
+	}{
+		{name: "hamilton active", convention: LibraryConvention, want: &Vec3{Y: 1}},
+		{name: "hamilton passive", convention: Convention{Multiplication: Hamilton, Interpretation: Passive}, want: &Vec3{Y: -1}},
+		{name: "jpl active", convention: Convention{Multiplication: JPL, Interpretation: Active}, want: &Vec3{Y: 1}},
+		{name: "jpl passive", convention: JPLConvention, want: &Vec3{Y: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.convention.Rotate(q, x); !got.Equals(tt.want, 1e-15) {
+				t.Errorf("Wrong result of Rotate. Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConvention_Components(t *testing.T) {
+	q := randomRotation()
+
+	for _, c := range allConventions() {
+		components := c.Components(q)
+		if got := c.Quaternion(components); !got.Equals(q, EqualsEpsilon) {
+			t.Errorf("Wrong result of component round trip in %+v. Expected %v, got %v", c, q, got)
+		}
+
+		// The stored quaternion rotates like q under its own convention.
+		stored := NewQuaternionByCoords(components[0], components[1], components[2], components[3])
+		if c.Storage == ScalarLast {
+			stored = NewQuaternionByCoords(components[3], components[0], components[1], components[2])
+		}
+		v := &Vec3{X: 1, Y: 2, Z: 3}
+		if got, want := c.Rotate(stored, v), LibraryConvention.Rotate(q, v); !got.Equals(want, 1e-12) {
+			t.Errorf("Wrong rotation of stored components in %+v. Expected %v, got %v", c, want, got)
+		}
+	}
+
+	if got := ROSConvention.Components(NewQuaternionByCoords(1, 2, 3, 4)); got != [4]float64{2, 3, 4, 1} {
+		t.Errorf("Wrong ROS components. Expected [2 3 4 1], got %v", got)
+	}
+	// JPL stores the conjugate of the Hamilton quaternion of a rotation.
+	if got := JPLConvention.Components(NewQuaternionByCoords(1, 2, 3, 4)); got != [4]float64{-2, -3, -4, 1} {
+		t.Errorf("Wrong JPL components. Expected [-2 -3 -4 1], got %v", got)
+	}
+}
+
+func TestConvention_Convert(t *testing.T) {
+	q := randomRotation()
+
+	for _, from := range allConventions() {
+		for _, to := range allConventions() {
+			converted := to.Convert(q, from)
+			if back := from.Convert(converted, to); !back.Equals(q, EqualsEpsilon) {
+				t.Errorf("Wrong result of Convert round trip from %+v to %+v. Expected %v, got %v", from, to, q, back)
+			}
+
+			v := &Vec3{X: 1, Y: -1, Z: 0.5}
+			if got, want := to.Rotate(converted, v), from.Rotate(q, v); !got.Equals(want, 1e-12) {
+				t.Errorf("Wrong rotation after Convert from %+v to %+v. Expected %v, got %v", from, to, want, got)
+			}
+		}
+	}
+}
+
+func TestConvention_Mul(t *testing.T) {
+	i, j := NewQuaternionByCoords(0, 1, 0, 0), NewQuaternionByCoords(0, 0, 1, 0)
+
+	if got := LibraryConvention.Mul(i, j); *got != *NewQuaternionByCoords(0, 0, 0, 1) {
+		t.Errorf("Wrong Hamilton product ij. Expected k, got %v", got)
+	}
+	if got := JPLConvention.Mul(i, j); *got != *NewQuaternionByCoords(0, 0, 0, -1) {
+		t.Errorf("Wrong JPL product ij. Expected -k, got %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+package go_quaternions
+
+import "math"
+
+// AngularDistance is the angle in [0, π] of the rotation taking q to arg.
+func (q *Quaternion) AngularDistance(arg *Quaternion) (float64, error) {
+	d, err := q.relative(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	return 2 * math.Atan2(math.Sqrt(d.I*d.I+d.J*d.J+d.K*d.K), math.Abs(d.W)), nil
+}
+
+// ChordalDistance is the Euclidean distance between the normalized
+// quaternions, taking the closer of arg and -arg. It equals 2*sin(θ/4) for
+// angular distance θ.
+func (q *Quaternion) ChordalDistance(arg *Quaternion) (float64, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return 0, err
+	}
+	normArg, err := arg.Normalize()
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Sqrt(math.Min(normQ.Sub(normArg).Norm(), normQ.Add(normArg).Norm())), nil
+}
+
+// RelativeRotation returns the axis and angle in [0, π] of d such that
+// arg = q*d, i.e. the rotation error of arg measured in the frame of q.
+func (q *Quaternion) RelativeRotation(arg *Quaternion) (*Vec3, float64, error) {
+	d, err := q.relative(arg)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return d.Canonical().ToAxisAngle()
+}
+
+// Distance combines the translation distance and the angular distance of
+// two poses as sqrt(dt² + (weight*θ)²), weight being length per radian.
+func (bq *BQuaternion) Distance(arg *BQuaternion, weight float64) (float64, error) {
+	angle, err := bq.P.AngularDistance(arg.P)
+	if err != nil {
+		return 0, err
+	}
+	dt := bq.Translation().Sub(arg.Translation()).Length()
+
+	return math.Hypot(dt, weight*angle), nil
+}
+
+func (q *Quaternion) relative(arg *Quaternion) (*Quaternion, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	normArg, err := arg.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	return normQ.Conjugate().MulByGrassmann(normArg), nil
+}
@@ -0,0 +1,111 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuaternion_AngularDistance(t *testing.T) {
+	tests := []struct {
+		name  string
+		axis  *Vec3
+		angle float64
+		want  float64
+		eps   float64
+	}{
+		{
+			name:  "identity",
+			axis:  &Vec3{X: 1},
+			angle: 0,
+			want:  0,
+			eps:   1e-12,
+		},
+		{
+			name:  "quarter turn",
+			axis:  &Vec3{X: 1, Y: 2, Z: 3},
+			angle: math.Pi / 2,
+			want:  math.Pi / 2,
+			eps:   1e-12,
+		},
+		{
+			name:  "more than half turn",
+			axis:  &Vec3{Z: 1},
+			angle: 3 * math.Pi / 2,
+			want:  math.Pi / 2,
+			eps:   1e-12,
+		},
+		{
+			name:  "tiny angle",
+			axis:  &Vec3{Y: 1},
+			angle: 1e-9,
+			want:  1e-9,
+			eps:   1e-15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQuaternionByCoords(rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64()).Normalize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			d, err := NewQuaternionByCoords(0, tt.axis.X, tt.axis.Y, tt.axis.Z).ToRotateQuaternion(tt.angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := q.AngularDistance(q.MulByGrassmann(d))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.want) > tt.eps {
+				t.Errorf("Wrong angular distance. Expected %v, got %v", tt.want, got)
+			}
+
+			chord, err := q.ChordalDistance(q.MulByGrassmann(d).MulByNumber(-1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := 2 * math.Sin(tt.want/4); math.Abs(chord-want) > 1e-12 {
+				t.Errorf("Wrong chordal distance. Expected %v, got %v", want, chord)
+			}
+
+			axis, angle, err := q.RelativeRotation(q.MulByGrassmann(d))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := NewQuaternionByCoords(0, axis.X, axis.Y, axis.Z).ToRotateQuaternion(angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(angle-tt.want) > tt.eps || !r.EqualsRotation(d, 1e-12) {
+				t.Errorf("Wrong relative rotation. Expected %v, got %v around %v", d, angle, axis)
+			}
+		})
+	}
+}
+
+func TestQuaternion_AngularDistance_ShouldFailForZero(t *testing.T) {
+	if _, err := NewQuaternionByCoords(1, 0, 0, 0).AngularDistance(NewQuaternionByCoords(0, 0, 0, 0)); err == nil {
+		t.Errorf("Expected error for zero quaternion")
+	}
+}
+
+func TestBQuaternion_Distance(t *testing.T) {
+	r1, _ := NewQuaternionByCoords(0, 0, 0, 1).ToRotateQuaternion(0.5)
+	r2, _ := NewQuaternionByCoords(0, 0, 0, 1).ToRotateQuaternion(0.8)
+	a := NewRigidBQuaternion(r1, &Vec3{X: 1, Y: 2, Z: 3})
+	b := NewRigidBQuaternion(r2, &Vec3{X: 4, Y: 6, Z: 3})
+
+	got, err := a.Distance(b, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := math.Hypot(5, 3); math.Abs(got-want) > 1e-12 {
+		t.Errorf("Wrong pose distance. Expected %v, got %v", want, got)
+	}
+
+	if !a.Translation().Equals(&Vec3{X: 1, Y: 2, Z: 3}, 1e-12) {
+		t.Errorf("Wrong translation of %v, got %v", a, a.Translation())
+	}
+}
@@ -0,0 +1,97 @@
+package dynamics
+
+import (
+	quat "go-quaternions"
+	"go-quaternions/lie"
+)
+
+// AngularVelocityFunc returns the world frame angular velocity at time t
+// and orientation q.
+type AngularVelocityFunc func(t float64, q *quat.Quaternion) *quat.Vec3
+
+// AttitudeIntegrator advances the attitude equation q' = ω(t, q)*q/2 from t
+// to t+dt.
+type AttitudeIntegrator interface {
+	Step(q *quat.Quaternion, t, dt float64, omega AngularVelocityFunc) *quat.Quaternion
+}
+
+// LieEuler is the first order step q ← Exp(dt ω(t, q)) q.
+type LieEuler struct{}
+
+func (LieEuler) Step(q *quat.Quaternion, t, dt float64, omega AngularVelocityFunc) *quat.Quaternion {
+	return lie.SO3Exp(scaled(omega(t, q), dt)).MulByGrassmann(q)
+}
+
+// RKMK4 is the fourth order Runge-Kutta-Munthe-Kaas method. It integrates
+// the rotation vector θ of the step in the Lie algebra, where
+// θ' = Jl⁻¹(θ) ω(t, Exp(θ) q), and applies Exp(θ) once at the end.
+type RKMK4 struct{}
+
+func (RKMK4) Step(q *quat.Quaternion, t, dt float64, omega AngularVelocityFunc) *quat.Quaternion {
+	stage := func(theta *quat.Vec3, t float64) *quat.Vec3 {
+		w := omega(t, lie.SO3Exp(theta).MulByGrassmann(q))
+		return scaled(lie.SO3LeftJacobianInverse(theta).MulVec(w), dt)
+	}
+
+	k1 := scaled(omega(t, q), dt)
+	k2 := stage(scaled(k1, 0.5), t+dt/2)
+	k3 := stage(scaled(k2, 0.5), t+dt/2)
+	k4 := stage(k3, t+dt)
+
+	theta := scaled(add(add(k1, scaled(k2, 2)), add(scaled(k3, 2), k4)), 1.0/6)
+
+	return lie.SO3Exp(theta).MulByGrassmann(q)
+}
+
+// CrouchGrossman is the third order Crouch-Grossman method, which composes
+// one exponential per stage instead of working in the Lie algebra.
+type CrouchGrossman struct{}
+
+func (CrouchGrossman) Step(q *quat.Quaternion, t, dt float64, omega AngularVelocityFunc) *quat.Quaternion {
+	exp := func(w *quat.Vec3, c float64, q *quat.Quaternion) *quat.Quaternion {
+		return lie.SO3Exp(scaled(w, c*dt)).MulByGrassmann(q)
+	}
+
+	k1 := omega(t, q)
+	k2 := omega(t+dt*3/4, exp(k1, 3.0/4, q))
+	k3 := omega(t+dt*17/24, exp(k2, 17.0/108, exp(k1, 119.0/216, q)))
+
+	return exp(k3, 24.0/17, exp(k2, -2.0/3, exp(k1, 13.0/51, q)))
+}
+
+// AdditiveRK4 is the classical Runge-Kutta method applied to the
+// quaternion components. It does not renormalize, so the orientation
+// slowly leaves the unit sphere; it serves as a reference for the geometric
+// methods.
+type AdditiveRK4 struct{}
+
+func (AdditiveRK4) Step(q *quat.Quaternion, t, dt float64, omega AngularVelocityFunc) *quat.Quaternion {
+	derivative := func(t float64, q *quat.Quaternion) *quat.Quaternion {
+		w := omega(t, q)
+		return quat.NewQuaternionByCoords(0, w.X/2, w.Y/2, w.Z/2).MulByGrassmann(q)
+	}
+
+	k1 := derivative(t, q)
+	k2 := derivative(t+dt/2, q.Add(k1.MulByNumber(dt/2)))
+	k3 := derivative(t+dt/2, q.Add(k2.MulByNumber(dt/2)))
+	k4 := derivative(t+dt, q.Add(k3.MulByNumber(dt)))
+
+	return q.Add(k1.Add(k2.MulByNumber(2)).Add(k3.MulByNumber(2)).Add(k4).MulByNumber(dt / 6))
+}
+
+// StepAttitude advances the body by dt like Step, but moves the orientation
+// with integrator and without renormalization. Forces and torques are
+// constant over the step, so momentum and translation are integrated
+// exactly.
+func (b *Body) StepAttitude(dt float64, integrator AttitudeIntegrator) {
+	acceleration := scaled(&b.force, 1/b.Mass)
+	momentum, torque := b.AngularMomentum, b.torque
+
+	b.Orientation = integrator.Step(b.Orientation, 0, dt, func(t float64, q *quat.Quaternion) *quat.Vec3 {
+		return b.angularVelocity(q, add(momentum, scaled(&torque, t)))
+	})
+	b.AngularMomentum = add(momentum, scaled(&torque, dt))
+	b.Position = add(b.Position, add(scaled(b.Velocity, dt), scaled(acceleration, dt*dt/2)))
+	b.Velocity = add(b.Velocity, scaled(acceleration, dt))
+	b.force, b.torque = quat.Vec3{}, quat.Vec3{}
+}
@@ -0,0 +1,113 @@
+package dynamics
+
+import (
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+	"testing"
+)
+
+func integrate(integrator AttitudeIntegrator, q *quat.Quaternion, duration float64, steps int, omega AngularVelocityFunc) *quat.Quaternion {
+	dt := duration / float64(steps)
+	for n := 0; n < steps; n++ {
+		q = integrator.Step(q, float64(n)*dt, dt, omega)
+	}
+
+	return q
+}
+
+func rotationError(a, b *quat.Quaternion) float64 {
+	phi, _ := lie.SO3Log(a.MulByGrassmann(b.Conjugate()))
+	return phi.Length()
+}
+
+func TestAttitudeIntegrator_ShouldBeExactForConstantRate(t *testing.T) {
+	omega := &quat.Vec3{X: 0.3, Y: -1, Z: 2}
+	constant := func(float64, *quat.Quaternion) *quat.Vec3 { return omega }
+	q := quat.NewQuaternionByCoords(0.5, 0.5, -0.5, 0.5)
+	want := lie.SO3Exp(scaled(omega, 3)).MulByGrassmann(q)
+
+	for _, integrator := range []AttitudeIntegrator{LieEuler{}, RKMK4{}, CrouchGrossman{}} {
+		if got := integrate(integrator, q, 3, 30, constant); rotationError(got, want) > 1e-13 {
+			t.Errorf("Wrong result of %T. Expected %v, got %v", integrator, want, got)
+		}
+	}
+}
+
+func TestAttitudeIntegrator_Order(t *testing.T) {
+	tests := []struct {
+		integrator AttitudeIntegrator
+		order      float64
+	}{
+		{integrator: LieEuler{}, order: 1},
+		{integrator: CrouchGrossman{}, order: 3},
+		{integrator: RKMK4{}, order: 4},
+		{integrator: AdditiveRK4{}, order: 4},
+	}
+
+	// A rate that depends on time and on the attitude.
+	omega := func(t float64, q *quat.Quaternion) *quat.Vec3 {
+		return &quat.Vec3{X: math.Cos(t), Y: math.Sin(2 * t), Z: 0.5 + q.I}
+	}
+	q := quat.NewQuaternionByCoords(1, 0, 0, 0)
+	want := integrate(RKMK4{}, q, 2, 20000, omega)
+
+	for _, tt := range tests {
+		coarse := rotationError(integrate(tt.integrator, q, 2, 50, omega), want)
+		fine := rotationError(integrate(tt.integrator, q, 2, 100, omega), want)
+
+		if order := math.Log2(coarse / fine); math.Abs(order-tt.order) > 0.3 {
+			t.Errorf("Wrong order of %T. Expected %v, got %v (errors %v and %v)", tt.integrator, tt.order, order, coarse, fine)
+		}
+	}
+}
+
+func TestBody_StepAttitude_NormDrift(t *testing.T) {
+	tests := []struct {
+		integrator AttitudeIntegrator
+		// maxDrift bounds |1 - |q|| after a long simulation.
+		maxDrift float64
+	}{
+		{integrator: LieEuler{}, maxDrift: 1e-10},
+		{integrator: RKMK4{}, maxDrift: 1e-10},
+		{integrator: CrouchGrossman{}, maxDrift: 1e-10},
+	}
+
+	simulate := func(integrator AttitudeIntegrator) *Body {
+		b, _ := NewBody(1, diagonal(1, 2, 3))
+		b.SetAngularVelocity(&quat.Vec3{X: 0.1, Y: 3, Z: 0.2})
+		for n := 0; n < 20000; n++ {
+			b.StepAttitude(0.05, integrator)
+		}
+
+		return b
+	}
+
+	additive := math.Abs(simulate(AdditiveRK4{}).Orientation.Norm() - 1)
+	if additive < 1e-5 {
+		t.Errorf("Expected visible norm drift of AdditiveRK4, got %v", additive)
+	}
+
+	for _, tt := range tests {
+		b := simulate(tt.integrator)
+		if drift := math.Abs(b.Orientation.Norm() - 1); drift > tt.maxDrift || drift > additive/1e4 {
+			t.Errorf("Wrong norm drift of %T. Expected below %v, got %v (AdditiveRK4 %v)", tt.integrator, tt.maxDrift, drift, additive)
+		}
+	}
+}
+
+func TestBody_StepAttitude_ShouldMatchStep(t *testing.T) {
+	a := spinningBody(t, diagonal(1, 2, 3))
+	b := *a
+
+	for n := 0; n < 1000; n++ {
+		a.ApplyForce(&quat.Vec3{X: 1, Z: 0.5}, &quat.Vec3{Y: 0.2})
+		b.ApplyForce(&quat.Vec3{X: 1, Z: 0.5}, &quat.Vec3{Y: 0.2})
+		a.Step(1e-3, RK4)
+		b.StepAttitude(1e-3, RKMK4{})
+	}
+
+	if rotationError(a.Orientation, b.Orientation) > 1e-9 || !a.Position.Equals(b.Position, 1e-12) || !a.AngularMomentum.Equals(b.AngularMomentum, 1e-12) {
+		t.Errorf("Wrong result of StepAttitude. Expected %v at %v, got %v at %v", a.Orientation, a.Position, b.Orientation, b.Position)
+	}
+}
@@ -0,0 +1,221 @@
+// Package dynamics simulates free rigid bodies.
+//
+// A Body keeps its state in the world frame: position and velocity of the
+// center of mass, the orientation Quaternion mapping body to world
+// coordinates and the angular momentum L. Euler's equations are integrated
+// in momentum form, L' = τ with ω = R I⁻¹ Rᵀ L, which keeps L exactly
+// constant without torque.
+package dynamics
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+)
+
+var (
+	NonPositiveMassError = errors.WithStack(errors.New("Mass must be positive"))
+	SingularInertiaError = errors.WithStack(errors.New("Inertia tensor is singular"))
+)
+
+type Method int
+
+const (
+	SemiImplicitEuler Method = iota
+	RK4
+)
+
+type Body struct {
+	Mass float64
+
+	Position        *quat.Vec3
+	Velocity        *quat.Vec3
+	Orientation     *quat.Quaternion
+	AngularMomentum *quat.Vec3
+
+	inertia        lie.Mat3
+	inverseInertia lie.Mat3
+
+	force, torque quat.Vec3
+}
+
+// NewBody returns a body at rest at the origin. The inertia tensor is taken
+// about the center of mass in body coordinates.
+func NewBody(mass float64, inertia lie.Mat3) (*Body, error) {
+	if mass <= 0 {
+		return nil, NonPositiveMassError
+	}
+	inverse, err := inverse3(inertia)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Body{
+		Mass:            mass,
+		Position:        &quat.Vec3{},
+		Velocity:        &quat.Vec3{},
+		Orientation:     quat.NewQuaternionByCoords(1, 0, 0, 0),
+		AngularMomentum: &quat.Vec3{},
+		inertia:         inertia,
+		inverseInertia:  inverse,
+	}, nil
+}
+
+func (b *Body) Inertia() lie.Mat3 {
+	return b.inertia
+}
+
+// WorldInertia returns R I Rᵀ.
+func (b *Body) WorldInertia() lie.Mat3 {
+	r := lie.SO3Matrix(b.Orientation)
+
+	return r.Mul(b.inertia).Mul(r.Transpose())
+}
+
+// AngularVelocity returns ω in world coordinates.
+func (b *Body) AngularVelocity() *quat.Vec3 {
+	return b.angularVelocity(b.Orientation, b.AngularMomentum)
+}
+
+// SetAngularVelocity sets the angular momentum that gives ω in world
+// coordinates.
+func (b *Body) SetAngularVelocity(omega *quat.Vec3) {
+	b.AngularMomentum = b.WorldInertia().MulVec(omega)
+}
+
+// BodyAngularVelocity returns ω in body coordinates.
+func (b *Body) BodyAngularVelocity() *quat.Vec3 {
+	r := lie.SO3Matrix(b.Orientation)
+
+	return b.inverseInertia.MulVec(r.Transpose().MulVec(b.AngularMomentum))
+}
+
+func (b *Body) LinearMomentum() *quat.Vec3 {
+	return scaled(b.Velocity, b.Mass)
+}
+
+// KineticEnergy returns the translational plus rotational energy.
+func (b *Body) KineticEnergy() float64 {
+	return b.Mass*dot(b.Velocity, b.Velocity)/2 + dot(b.AngularVelocity(), b.AngularMomentum)/2
+}
+
+// ApplyForce adds a world frame force acting at a point given in body
+// coordinates until the next Step.
+func (b *Body) ApplyForce(force, point *quat.Vec3) {
+	arm := lie.SO3Matrix(b.Orientation).MulVec(point)
+
+	b.force = *add(&b.force, force)
+	b.torque = *add(&b.torque, cross(arm, force))
+}
+
+// ApplyCentralForce adds a world frame force acting at the center of mass.
+func (b *Body) ApplyCentralForce(force *quat.Vec3) {
+	b.force = *add(&b.force, force)
+}
+
+// ApplyTorque adds a world frame torque until the next Step.
+func (b *Body) ApplyTorque(torque *quat.Vec3) {
+	b.torque = *add(&b.torque, torque)
+}
+
+// Step advances the body by dt with the forces and torques applied since the
+// previous step held constant, then clears them.
+func (b *Body) Step(dt float64, method Method) {
+	s := state{position: b.Position, velocity: b.Velocity, orientation: b.Orientation, angularMomentum: b.AngularMomentum}
+
+	switch method {
+	case RK4:
+		k1 := b.derivative(s)
+		k2 := b.derivative(s.add(k1, dt/2))
+		k3 := b.derivative(s.add(k2, dt/2))
+		k4 := b.derivative(s.add(k3, dt))
+		s = s.add(k1, dt/6).add(k2, dt/3).add(k3, dt/3).add(k4, dt/6)
+	default:
+		// Velocities first, then positions from the new velocities.
+		s.velocity = add(s.velocity, scaled(&b.force, dt/b.Mass))
+		s.angularMomentum = add(s.angularMomentum, scaled(&b.torque, dt))
+		s.position = add(s.position, scaled(s.velocity, dt))
+		s.orientation = s.orientation.Add(b.derivative(s).orientation.MulByNumber(dt))
+	}
+
+	if q, err := s.orientation.Normalize(); err == nil {
+		s.orientation = q
+	}
+	b.Position, b.Velocity, b.Orientation, b.AngularMomentum = s.position, s.velocity, s.orientation, s.angularMomentum
+	b.force, b.torque = quat.Vec3{}, quat.Vec3{}
+}
+
+type state struct {
+	position, velocity, angularMomentum *quat.Vec3
+	orientation                         *quat.Quaternion
+}
+
+func (s state) add(d state, h float64) state {
+	return state{
+		position:        add(s.position, scaled(d.position, h)),
+		velocity:        add(s.velocity, scaled(d.velocity, h)),
+		angularMomentum: add(s.angularMomentum, scaled(d.angularMomentum, h)),
+		orientation:     s.orientation.Add(d.orientation.MulByNumber(h)),
+	}
+}
+
+// derivative returns the time derivative of s, with q' = ω*q/2.
+func (b *Body) derivative(s state) state {
+	omega := b.angularVelocity(s.orientation, s.angularMomentum)
+
+	return state{
+		position:        s.velocity,
+		velocity:        scaled(&b.force, 1/b.Mass),
+		angularMomentum: &b.torque,
+		orientation:     quat.NewQuaternionByCoords(0, omega.X/2, omega.Y/2, omega.Z/2).MulByGrassmann(s.orientation),
+	}
+}
+
+func (b *Body) angularVelocity(orientation *quat.Quaternion, momentum *quat.Vec3) *quat.Vec3 {
+	// Intermediate RK4 stages leave the unit sphere slightly.
+	if q, err := orientation.Normalize(); err == nil {
+		orientation = q
+	}
+	r := lie.SO3Matrix(orientation)
+
+	return r.MulVec(b.inverseInertia.MulVec(r.Transpose().MulVec(momentum)))
+}
+
+func inverse3(m lie.Mat3) (lie.Mat3, error) {
+	var c lie.Mat3
+	for r := 0; r < 3; r++ {
+		for k := 0; k < 3; k++ {
+			r1, r2 := (r+1)%3, (r+2)%3
+			k1, k2 := (k+1)%3, (k+2)%3
+			c[k][r] = m[r1][k1]*m[r2][k2] - m[r1][k2]*m[r2][k1]
+		}
+	}
+
+	det := m[0][0]*c[0][0] + m[0][1]*c[1][0] + m[0][2]*c[2][0]
+	if math.Abs(det) < 1e-300 {
+		return lie.Mat3{}, SingularInertiaError
+	}
+
+	return c.Scale(1 / det), nil
+}
+
+func add(a, b *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z}
+}
+
+func scaled(v *quat.Vec3, s float64) *quat.Vec3 {
+	return &quat.Vec3{X: s * v.X, Y: s * v.Y, Z: s * v.Z}
+}
+
+func dot(a, b *quat.Vec3) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func cross(a, b *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+}
@@ -0,0 +1,154 @@
+package dynamics
+
+import (
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func diagonal(a, b, c float64) lie.Mat3 {
+	return lie.Mat3{{a, 0, 0}, {0, b, 0}, {0, 0, c}}
+}
+
+func spinningBody(t *testing.T, inertia lie.Mat3) *Body {
+	b, err := NewBody(2, inertia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Orientation, _ = quat.NewQuaternionByCoords(rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64()).Normalize()
+	b.SetAngularVelocity(&quat.Vec3{X: 0.3, Y: 2, Z: 0.4})
+	b.Velocity = &quat.Vec3{X: 1, Y: -1}
+
+	return b
+}
+
+func TestBody_Step_ShouldConserveEnergyAndMomentum(t *testing.T) {
+	tests := []struct {
+		name   string
+		method Method
+		// energyDrift is the allowed relative change of kinetic energy.
+		energyDrift float64
+	}{
+		{name: "semi-implicit euler", method: SemiImplicitEuler, energyDrift: 1e-2},
+		{name: "rk4", method: RK4, energyDrift: 1e-9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Spinning close to the intermediate axis tumbles the body,
+			// which exercises the coupling in Euler's equations.
+			b := spinningBody(t, diagonal(1, 2, 3))
+			energy := b.KineticEnergy()
+			momentum := *b.AngularMomentum
+			linear := b.LinearMomentum()
+			bodyMomentum := b.inertia.MulVec(b.BodyAngularVelocity()).Length()
+
+			for n := 0; n < 10000; n++ {
+				b.Step(1e-3, tt.method)
+			}
+
+			if drift := math.Abs(b.KineticEnergy()-energy) / energy; drift > tt.energyDrift {
+				t.Errorf("Wrong kinetic energy. Expected %v, got %v (drift %v)", energy, b.KineticEnergy(), drift)
+			}
+			if *b.AngularMomentum != momentum || !b.LinearMomentum().Equals(linear, 1e-15) {
+				t.Errorf("Wrong momentum. Expected %v and %v, got %v and %v", momentum, linear, b.AngularMomentum, b.LinearMomentum())
+			}
+			if got := b.inertia.MulVec(b.BodyAngularVelocity()).Length(); math.Abs(got-bodyMomentum) > 1e-12 {
+				t.Errorf("Wrong body frame momentum. Expected %v, got %v", bodyMomentum, got)
+			}
+			if math.Abs(b.Orientation.Norm()-1) > 1e-15 {
+				t.Errorf("Wrong orientation norm %v", b.Orientation.Norm())
+			}
+			if want := (&quat.Vec3{X: 10, Y: -10}); !b.Position.Equals(want, 1e-9) {
+				t.Errorf("Wrong position. Expected %v, got %v", want, b.Position)
+			}
+		})
+	}
+}
+
+func TestBody_Step_TorqueFreePrecession(t *testing.T) {
+	// A symmetric body spins about its figure axis at a constant rate while
+	// the transverse body rates rotate at Ω = (I3 - I1) / I1 * ω3.
+	i1, i3 := 2.0, 5.0
+	b, _ := NewBody(1, diagonal(i1, i1, i3))
+	omega := &quat.Vec3{X: 0.5, Y: 0, Z: 3}
+	b.SetAngularVelocity(omega)
+
+	rate := (i3 - i1) / i1 * omega.Z
+	dt := 1e-3
+	for n := 1; n <= 5000; n++ {
+		b.Step(dt, RK4)
+
+		if n%500 != 0 {
+			continue
+		}
+		phase := rate * float64(n) * dt
+		want := &quat.Vec3{X: omega.X * math.Cos(phase), Y: omega.X * math.Sin(phase), Z: omega.Z}
+		if got := b.BodyAngularVelocity(); !got.Equals(want, 1e-8) {
+			t.Fatalf("Wrong body angular velocity at t = %v. Expected %v, got %v", float64(n)*dt, want, got)
+		}
+	}
+}
+
+func TestBody_ApplyForce(t *testing.T) {
+	b, _ := NewBody(2, diagonal(1, 1, 1))
+	b.Orientation = quat.NewQuaternionByCoords(math.Sqrt2/2, 0, 0, math.Sqrt2/2)
+
+	// The body point (1, 0, 0) sits at (0, 1, 0) in the world after the
+	// quarter turn about Z, so a push along X spins the body about -Z.
+	b.ApplyForce(&quat.Vec3{X: 4}, &quat.Vec3{X: 1})
+	b.ApplyTorque(&quat.Vec3{X: 1})
+	b.Step(0.5, SemiImplicitEuler)
+
+	if want := (&quat.Vec3{X: 1}); !b.Velocity.Equals(want, 1e-15) {
+		t.Errorf("Wrong velocity. Expected %v, got %v", want, b.Velocity)
+	}
+	if want := (&quat.Vec3{X: 0.5, Z: -2}); !b.AngularMomentum.Equals(want, 1e-15) {
+		t.Errorf("Wrong angular momentum. Expected %v, got %v", want, b.AngularMomentum)
+	}
+
+	velocity := *b.Velocity
+	b.Step(0.5, SemiImplicitEuler)
+	if *b.Velocity != velocity {
+		t.Errorf("Expected forces to be cleared by Step, got velocity %v", b.Velocity)
+	}
+}
+
+func TestBody_Step_FreeFall(t *testing.T) {
+	b, _ := NewBody(3, diagonal(1, 2, 3))
+	gravity := &quat.Vec3{Z: -9.81}
+
+	dt := 0.01
+	for n := 0; n < 100; n++ {
+		b.ApplyCentralForce(scaled(gravity, b.Mass))
+		b.Step(dt, RK4)
+	}
+
+	if want := (&quat.Vec3{Z: -9.81 / 2}); !b.Position.Equals(want, 1e-12) {
+		t.Errorf("Wrong position after free fall. Expected %v, got %v", want, b.Position)
+	}
+	if b.AngularMomentum.Length() != 0 {
+		t.Errorf("Expected no rotation, got %v", b.AngularMomentum)
+	}
+}
+
+func TestNewBody_ShouldRejectInvalidParameters(t *testing.T) {
+	if _, err := NewBody(0, diagonal(1, 1, 1)); err == nil {
+		t.Errorf("Expected error for zero mass")
+	}
+	if _, err := NewBody(1, lie.Mat3{{1, 1, 0}, {1, 1, 0}, {0, 0, 1}}); err == nil {
+		t.Errorf("Expected error for singular inertia")
+	}
+
+	inertia := lie.Mat3{{2, 0.1, 0}, {0.1, 3, 0.2}, {0, 0.2, 4}}
+	inverse, _ := inverse3(inertia)
+	product := inertia.Mul(inverse)
+	for r := range product {
+		for c := range product[r] {
+			if math.Abs(product[r][c]-lie.Identity3()[r][c]) > 1e-15 {
+				t.Fatalf("Wrong result of inverse3. Expected identity, got %v", product)
+			}
+		}
+	}
+}
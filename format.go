@@ -0,0 +1,164 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter. The verbs v, e, E, f, F, g and G apply
+// width and precision to every component; s prints String(). Flags select
+// the layout:
+//
+//	%v   1+2i-3j+4k
+//	%#v  (1, 2, -3, 4)
+//	%+v  axisangle(0.5, 0.5, 0.7071, 120deg)
+//
+// Both the default and the axis-angle layouts are accepted by ParseQuaternion.
+// The zero quaternion has no axis, so %+v prints it in the default layout.
+// Format has a value receiver, so Quaternion values and value fields of
+// structs format alike.
+func (q Quaternion) Format(f fmt.State, verb rune) {
+	format, ok := componentFormat(f, verb)
+	if !ok {
+		writeFormatFallback(f, verb, q.String())
+		return
+	}
+
+	switch {
+	case f.Flag('+'):
+		writeAxisAngle(f, format, &q)
+	case f.Flag('#'):
+		writeTuple(f, format, q.W, q.I, q.J, q.K)
+	default:
+		writeHamilton(f, format, &q)
+	}
+}
+
+// Format implements fmt.Formatter with the same verbs and flags as for
+// Quaternion, printing the pose on a single line:
+//
+//	%v   (1+0i+0j+0k)+ε(0+0.5i+0j+0k)
+//	%#v  ((1, 0, 0, 0), (0, 0.5, 0, 0))
+//	%+v  axisangle(1, 0, 0, 0deg) translation(1, 0, 0)
+func (bq BQuaternion) Format(f fmt.State, verb rune) {
+	format, ok := componentFormat(f, verb)
+	if !ok {
+		writeFormatFallback(f, verb, bq.String())
+		return
+	}
+
+	switch {
+	case f.Flag('+'):
+		writeAxisAngle(f, format, bq.P)
+		fmt.Fprint(f, " translation")
+		t := bq.Translation()
+		writeTuple(f, format, t.X, t.Y, t.Z)
+	case f.Flag('#'):
+		fmt.Fprint(f, "(")
+		writeTuple(f, format, bq.P.W, bq.P.I, bq.P.J, bq.P.K)
+		fmt.Fprint(f, ", ")
+		writeTuple(f, format, bq.Q.W, bq.Q.I, bq.Q.J, bq.Q.K)
+		fmt.Fprint(f, ")")
+	default:
+		fmt.Fprint(f, "(")
+		writeHamilton(f, format, bq.P)
+		fmt.Fprint(f, ")+ε(")
+		writeHamilton(f, format, bq.Q)
+		fmt.Fprint(f, ")")
+	}
+}
+
+// Format implements fmt.Formatter, printing (x, y, z) with width and
+// precision applied to every component.
+func (v Vec3) Format(f fmt.State, verb rune) {
+	format, ok := componentFormat(f, verb)
+	if !ok {
+		writeFormatFallback(f, verb, v.String())
+		return
+	}
+
+	writeTuple(f, format, v.X, v.Y, v.Z)
+}
+
+// componentFormat rebuilds the float verb for a single component. It reports
+// false for s and for verbs that are not meaningful for floats.
+func componentFormat(f fmt.State, verb rune) (string, bool) {
+	switch verb {
+	case 'v':
+		verb = 'g'
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+	default:
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "- 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+
+	return b.String(), true
+}
+
+func writeFormatFallback(f fmt.State, verb rune, s string) {
+	if verb == 's' {
+		fmt.Fprint(f, s)
+		return
+	}
+
+	fmt.Fprintf(f, "%%!%c(%s)", verb, s)
+}
+
+func writeHamilton(f fmt.State, format string, q *Quaternion) {
+	fmt.Fprintf(f, format, q.W)
+	for n, c := range [3]float64{q.I, q.J, q.K} {
+		if math.Signbit(c) {
+			fmt.Fprint(f, "-")
+			c = -c
+		} else {
+			fmt.Fprint(f, "+")
+		}
+		// fmt always signs infinities, which would double the operator.
+		fmt.Fprint(f, strings.Replace(fmt.Sprintf(format, c), "+Inf", "Inf", 1))
+		fmt.Fprint(f, [3]string{"i", "j", "k"}[n])
+	}
+}
+
+func writeTuple(f fmt.State, format string, values ...float64) {
+	fmt.Fprint(f, "(")
+	for n, c := range values {
+		if n > 0 {
+			fmt.Fprint(f, ", ")
+		}
+		fmt.Fprintf(f, format, c)
+	}
+	fmt.Fprint(f, ")")
+}
+
+func writeAxisAngle(f fmt.State, format string, q *Quaternion) {
+	axis, angle, err := q.ToAxisAngle()
+	if err != nil {
+		writeHamilton(f, format, q)
+		return
+	}
+
+	fmt.Fprint(f, "axisangle(")
+	for _, c := range [3]float64{axis.X, axis.Y, axis.Z} {
+		fmt.Fprintf(f, format, c)
+		fmt.Fprint(f, ", ")
+	}
+	fmt.Fprintf(f, format, angle*180/math.Pi)
+	fmt.Fprint(f, "deg)")
+}
@@ -0,0 +1,113 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestQuaternion_Format(t *testing.T) {
+	q := NewQuaternionByCoords(1, 2.5, -3, 1.0/3)
+
+	tests := []struct {
+		name   string
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{name: "hamilton", format: "%v", arg: q, want: "1+2.5i-3j+0.3333333333333333k"},
+		{name: "hamilton with precision", format: "%.3v", arg: q, want: "1+2.5i-3j+0.333k"},
+		{name: "fixed point", format: "%.2f", arg: q, want: "1.00+2.50i-3.00j+0.33k"},
+		{name: "exponent", format: "%.1e", arg: q, want: "1.0e+00+2.5e+00i-3.0e+00j+3.3e-01k"},
+		{name: "tuple", format: "%#.3g", arg: q, want: "(1, 2.5, -3, 0.333)"},
+		{name: "tuple with width", format: "%#6.2f", arg: q, want: "(  1.00,   2.50,  -3.00,   0.33)"},
+		{name: "axis angle", format: "%+.4v", arg: NewQuaternionByCoords(math.Cos(math.Pi/4), 0, 0, -math.Sin(math.Pi/4)), want: "axisangle(0, 0, -1, 90deg)"},
+		{name: "string", format: "%s", arg: NewQuaternionByCoords(1, 2, 3, 4), want: "(1)+(2)i+(3)j+(4)k"},
+		{name: "bad verb", format: "%d", arg: NewQuaternionByCoords(1, 2, 3, 4), want: "%!d((1)+(2)i+(3)j+(4)k)"},
+		{name: "negative zero", format: "%v", arg: NewQuaternionByCoords(0, math.Copysign(0, -1), 0, 0), want: "0-0i+0j+0k"},
+		{
+			name:   "bquaternion",
+			format: "%.2v",
+			arg:    NewBQuaternion(NewQuaternionByCoords(1, 0, 0, 0), NewQuaternionByCoords(0, 0.5, -1, 0)),
+			want:   "(1+0i+0j+0k)+ε(0+0.5i-1j+0k)",
+		},
+		{
+			name:   "bquaternion tuple",
+			format: "%#v",
+			arg:    NewBQuaternion(NewQuaternionByCoords(1, 0, 0, 0), NewQuaternionByCoords(0, 0.5, -1, 0)),
+			want:   "((1, 0, 0, 0), (0, 0.5, -1, 0))",
+		},
+		{
+			name:   "bquaternion axis angle",
+			format: "%+.3g",
+			arg:    NewBQuaternion(NewQuaternionByCoords(0, 0, 0, 1), NewQuaternionByCoords(0, 0.5, -1, 0).MulByGrassmann(NewQuaternionByCoords(0, 0, 0, 1))),
+			want:   "axisangle(0, 0, 1, 180deg) translation(1, -2, 0)",
+		},
+		{name: "vec3", format: "%.2f", arg: &Vec3{X: 1, Y: -2, Z: 1.0 / 3}, want: "(1.00, -2.00, 0.33)"},
+		{name: "vec3 string", format: "%s", arg: &Vec3{X: 1, Y: -2, Z: 3}, want: "(1, -2, 3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+				t.Errorf("Wrong result of %q. Expected %q, got %q", tt.format, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuaternion_Format_ShouldBeParsable(t *testing.T) {
+	q, err := NewQuaternionByCoords(0, 1, -2, 0.5).ToRotateQuaternion(1.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{"%v", "%+v"} {
+		got, err := ParseQuaternion(fmt.Sprintf(format, q))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equals(q, 1e-12) {
+			t.Errorf("Wrong result of round trip with %q. Expected %v, got %v", format, q, got)
+		}
+	}
+}
+
+func TestQuaternion_Format_ShouldParseInfinities(t *testing.T) {
+	q := NewQuaternionByCoords(math.Inf(1), math.Inf(-1), math.Inf(1), 2)
+
+	for _, format := range []string{"%v", "%.3f", "%g"} {
+		s := fmt.Sprintf(format, q)
+		got, err := ParseQuaternion(s)
+		if err != nil {
+			t.Fatalf("Wrong result of parsing %q: %v", s, err)
+		}
+		if *got != *q {
+			t.Errorf("Wrong result of round trip with %q. Expected %v, got %v", format, q, got)
+		}
+	}
+}
+
+func TestQuaternion_Format_ZeroShouldBeParsable(t *testing.T) {
+	s := fmt.Sprintf("%+v", NewQuaternionByCoords(0, 0, 0, 0))
+	got, err := ParseQuaternion(s)
+	if err != nil {
+		t.Fatalf("Wrong result of parsing %q: %v", s, err)
+	}
+	if *got != (Quaternion{}) {
+		t.Errorf("Wrong result of round trip. Expected zero, got %v", got)
+	}
+}
+
+func TestFormat_ShouldNotDependOnAddressability(t *testing.T) {
+	pose := struct {
+		Rotation Quaternion
+		Position Vec3
+	}{Rotation: Quaternion{W: 1, K: 2}, Position: Vec3{X: 3}}
+
+	if got, want := fmt.Sprintf("%v", pose), "{1+0i+0j+2k (3, 0, 0)}"; got != want {
+		t.Errorf("Wrong result of %%v for struct. Expected %q, got %q", want, got)
+	}
+	if got, want := fmt.Sprintf("%.1f", pose.Rotation), "1.0+0.0i+0.0j+2.0k"; got != want {
+		t.Errorf("Wrong result of %%.1f for value. Expected %q, got %q", want, got)
+	}
+}
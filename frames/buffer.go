@@ -0,0 +1,267 @@
+// Package frames keeps a tree of named coordinate frames linked by
+// timestamped BQuaternion transforms, in the spirit of ROS tf.
+//
+// A transform stored for (parent, child) maps points given in the child
+// frame to the parent frame. Lookup(target, source, t) returns the transform
+// mapping points from source to target at time t.
+package frames
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+	"sort"
+)
+
+var (
+	UnknownFrameError  = errors.WithStack(errors.New("Unknown frame"))
+	NotConnectedError  = errors.WithStack(errors.New("Frames are not connected"))
+	CycleError         = errors.WithStack(errors.New("Transform would create a cycle"))
+	ParentChangedError = errors.WithStack(errors.New("Frame already has a different parent"))
+	CacheTimeError     = errors.WithStack(errors.New("Cache time must be a non-negative number"))
+)
+
+// ExtrapolationError is returned when a transform is requested outside the
+// time span stored for one of the links on the path.
+type ExtrapolationError struct {
+	Parent, Child    string
+	Time             float64
+	Earliest, Latest float64
+}
+
+func (e *ExtrapolationError) Error() string {
+	return fmt.Sprintf("lookup of %s -> %s at %v would extrapolate outside [%v, %v]", e.Parent, e.Child, e.Time, e.Earliest, e.Latest)
+}
+
+type sample struct {
+	time      float64
+	transform *quat.BQuaternion
+}
+
+type link struct {
+	parent  string
+	static  bool
+	samples []sample
+}
+
+type Buffer struct {
+	// CacheTime is how many seconds of history are kept per link, counted
+	// back from its newest sample.
+	CacheTime float64
+
+	links map[string]*link
+}
+
+func NewBuffer(cacheTime float64) (*Buffer, error) {
+	if cacheTime < 0 || math.IsNaN(cacheTime) {
+		return nil, errors.Wrapf(CacheTimeError, "cache time %v", cacheTime)
+	}
+
+	return &Buffer{
+		CacheTime: cacheTime,
+		links:     map[string]*link{},
+	}, nil
+}
+
+// Set records the transform from child to parent at time t.
+func (b *Buffer) Set(parent, child string, t float64, transform *quat.BQuaternion) error {
+	l, err := b.link(parent, child, false)
+	if err != nil {
+		return err
+	}
+
+	n := sort.Search(len(l.samples), func(k int) bool { return l.samples[k].time >= t })
+	if n < len(l.samples) && l.samples[n].time == t {
+		l.samples[n].transform = transform
+	} else {
+		l.samples = append(l.samples, sample{})
+		copy(l.samples[n+1:], l.samples[n:])
+		l.samples[n] = sample{time: t, transform: transform}
+	}
+
+	oldest := l.samples[len(l.samples)-1].time - b.CacheTime
+	drop := sort.Search(len(l.samples), func(k int) bool { return l.samples[k].time >= oldest })
+	if drop == len(l.samples) {
+		// The newest sample always stays, whatever CacheTime holds.
+		drop--
+	}
+	l.samples = append(l.samples[:0], l.samples[drop:]...)
+
+	return nil
+}
+
+// SetStatic records a transform from child to parent valid at all times.
+func (b *Buffer) SetStatic(parent, child string, transform *quat.BQuaternion) error {
+	l, err := b.link(parent, child, true)
+	if err != nil {
+		return err
+	}
+	l.samples = []sample{{transform: transform}}
+
+	return nil
+}
+
+// Frames returns the names of all known frames.
+func (b *Buffer) Frames() []string {
+	seen := map[string]bool{}
+	for child, l := range b.links {
+		seen[child] = true
+		seen[l.parent] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Lookup returns the transform mapping points in the source frame to the
+// target frame at time t, chaining and interpolating the stored transforms
+// through the closest common ancestor.
+func (b *Buffer) Lookup(target, source string, t float64) (*quat.BQuaternion, error) {
+	if !b.known(target) {
+		return nil, errors.Wrapf(UnknownFrameError, "frame %s", target)
+	}
+	if !b.known(source) {
+		return nil, errors.Wrapf(UnknownFrameError, "frame %s", source)
+	}
+
+	targetPath, sourcePath := b.pathToRoot(target), b.pathToRoot(source)
+
+	depth := map[string]int{}
+	for k, name := range targetPath {
+		depth[name] = k
+	}
+
+	common := -1
+	for k, name := range sourcePath {
+		if _, ok := depth[name]; ok {
+			common = k
+			break
+		}
+	}
+	if common < 0 {
+		return nil, errors.Wrapf(NotConnectedError, "%s and %s", target, source)
+	}
+
+	// Both transforms map into the common ancestor frame.
+	fromSource, err := b.chain(sourcePath[:common+1], t)
+	if err != nil {
+		return nil, err
+	}
+	fromTarget, err := b.chain(targetPath[:depth[sourcePath[common]]+1], t)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromTarget.ComplexConjugate().Mul(fromSource), nil
+}
+
+func (b *Buffer) link(parent, child string, static bool) (*link, error) {
+	if parent == child {
+		return nil, errors.Wrapf(CycleError, "%s -> %s", parent, child)
+	}
+
+	l, ok := b.links[child]
+	if ok {
+		if l.parent != parent {
+			return nil, errors.Wrapf(ParentChangedError, "%s has parent %s, not %s", child, l.parent, parent)
+		}
+		if l.static != static {
+			l.static, l.samples = static, nil
+		}
+		return l, nil
+	}
+
+	for _, name := range b.pathToRoot(parent) {
+		if name == child {
+			return nil, errors.Wrapf(CycleError, "%s -> %s", parent, child)
+		}
+	}
+
+	l = &link{parent: parent, static: static}
+	b.links[child] = l
+
+	return l, nil
+}
+
+func (b *Buffer) known(frame string) bool {
+	if _, ok := b.links[frame]; ok {
+		return true
+	}
+	for _, l := range b.links {
+		if l.parent == frame {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathToRoot lists frame and its ancestors, nearest first.
+func (b *Buffer) pathToRoot(frame string) []string {
+	path := []string{frame}
+	for l, ok := b.links[frame]; ok; l, ok = b.links[l.parent] {
+		path = append(path, l.parent)
+	}
+
+	return path
+}
+
+// chain composes the transforms from path[0] up to path[len(path)-1].
+func (b *Buffer) chain(path []string, t float64) (*quat.BQuaternion, error) {
+	res := quat.NewBQuaternion(quat.NewQuaternionByCoords(1, 0, 0, 0), quat.NewQuaternionByCoords(0, 0, 0, 0))
+
+	for _, child := range path[:len(path)-1] {
+		tf, err := b.links[child].at(child, t)
+		if err != nil {
+			return nil, err
+		}
+		res = tf.Mul(res)
+	}
+
+	return res, nil
+}
+
+func (l *link) at(child string, t float64) (*quat.BQuaternion, error) {
+	if l.static {
+		return l.samples[0].transform, nil
+	}
+
+	first, last := l.samples[0], l.samples[len(l.samples)-1]
+	if t < first.time || t > last.time {
+		return nil, &ExtrapolationError{Parent: l.parent, Child: child, Time: t, Earliest: first.time, Latest: last.time}
+	}
+
+	n := sort.Search(len(l.samples), func(k int) bool { return l.samples[k].time >= t })
+	if l.samples[n].time == t {
+		return l.samples[n].transform, nil
+	}
+
+	a, c := l.samples[n-1], l.samples[n]
+	return interpolate(a.transform, c.transform, (t-a.time)/(c.time-a.time))
+}
+
+// interpolate moves along the shortest rotation arc and linearly in
+// translation.
+func interpolate(a, b *quat.BQuaternion, s float64) (*quat.BQuaternion, error) {
+	delta, err := lie.SO3Log(a.P.Conjugate().MulByGrassmann(b.P))
+	if err != nil {
+		return nil, err
+	}
+	rotation := a.P.MulByGrassmann(lie.SO3Exp(&quat.Vec3{X: s * delta.X, Y: s * delta.Y, Z: s * delta.Z}))
+
+	ta, tb := a.Translation(), b.Translation()
+	translation := &quat.Vec3{
+		X: ta.X + s*(tb.X-ta.X),
+		Y: ta.Y + s*(tb.Y-ta.Y),
+		Z: ta.Z + s*(tb.Z-ta.Z),
+	}
+
+	return quat.NewRigidBQuaternion(rotation, translation), nil
+}
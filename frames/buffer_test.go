@@ -0,0 +1,193 @@
+package frames
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"math"
+	"testing"
+)
+
+func rotationZ(angle float64) *quat.Quaternion {
+	return quat.NewQuaternionByCoords(math.Cos(angle/2), 0, 0, math.Sin(angle/2))
+}
+
+func pose(angle float64, t *quat.Vec3) *quat.BQuaternion {
+	return quat.NewRigidBQuaternion(rotationZ(angle), t)
+}
+
+// robotBuffer has world -> base moving from x=0 to x=2 while turning a
+// quarter turn, and static base -> laser and base -> camera mounts.
+func robotBuffer(t *testing.T) *Buffer {
+	b, err := NewBuffer(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		parent, child string
+		time          float64
+		transform     *quat.BQuaternion
+	}{
+		{"world", "base", 1, pose(math.Pi/2, &quat.Vec3{X: 2})},
+		{"world", "base", 0, pose(0, &quat.Vec3{})},
+	}
+	for _, s := range steps {
+		if err := b.Set(s.parent, s.child, s.time, s.transform); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.SetStatic("base", "laser", pose(0, &quat.Vec3{X: 0.5})); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetStatic("base", "camera", pose(math.Pi, &quat.Vec3{Z: 1})); err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestBuffer_Lookup(t *testing.T) {
+	b := robotBuffer(t)
+
+	tests := []struct {
+		name           string
+		target, source string
+		time           float64
+		point, want    *quat.Vec3
+	}{
+		{
+			name:   "interpolated chain",
+			target: "world", source: "laser", time: 0.5,
+			point: &quat.Vec3{},
+			want:  &quat.Vec3{X: 1 + 0.5*math.Sqrt2/2, Y: 0.5 * math.Sqrt2 / 2},
+		},
+		{
+			name:   "exact sample",
+			target: "world", source: "laser", time: 1,
+			point: &quat.Vec3{X: 1},
+			want:  &quat.Vec3{X: 2, Y: 1.5},
+		},
+		{
+			name:   "inverse chain",
+			target: "laser", source: "world", time: 0,
+			point: &quat.Vec3{X: 3, Y: 1},
+			want:  &quat.Vec3{X: 2.5, Y: 1},
+		},
+		{
+			name:   "siblings",
+			target: "laser", source: "camera", time: 0.3,
+			point: &quat.Vec3{X: 1},
+			want:  &quat.Vec3{X: -1.5, Z: 1},
+		},
+		{
+			name:   "same frame",
+			target: "base", source: "base", time: 7,
+			point: &quat.Vec3{X: 1, Y: 2, Z: 3},
+			want:  &quat.Vec3{X: 1, Y: 2, Z: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := b.Lookup(tt.target, tt.source, tt.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tf.Compile().Apply(tt.point); !got.Equals(tt.want, 1e-12) {
+				t.Errorf("Wrong transform of %v. Expected %v, got %v", tt.point, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBuffer_Lookup_ShouldRejectExtrapolation(t *testing.T) {
+	b := robotBuffer(t)
+
+	_, err := b.Lookup("camera", "world", 1.5)
+
+	var extrapolation *ExtrapolationError
+	if !errors.As(err, &extrapolation) {
+		t.Fatalf("Expected ExtrapolationError, got %v", err)
+	}
+	if extrapolation.Child != "base" || extrapolation.Latest != 1 {
+		t.Errorf("Wrong extrapolation error %+v", extrapolation)
+	}
+}
+
+func TestBuffer_Lookup_ShouldFailForUnknownOrDisconnectedFrames(t *testing.T) {
+	b := robotBuffer(t)
+	if err := b.SetStatic("odom", "imu", pose(0, &quat.Vec3{})); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Lookup("world", "gps", 0); !errors.Is(err, UnknownFrameError) {
+		t.Errorf("Expected UnknownFrameError, got %v", err)
+	}
+	if _, err := b.Lookup("world", "imu", 0); !errors.Is(err, NotConnectedError) {
+		t.Errorf("Expected NotConnectedError, got %v", err)
+	}
+}
+
+func TestBuffer_Set_ShouldRejectCyclesAndReparenting(t *testing.T) {
+	b := robotBuffer(t)
+
+	if err := b.Set("laser", "world", 0, pose(0, &quat.Vec3{})); !errors.Is(err, CycleError) {
+		t.Errorf("Expected CycleError, got %v", err)
+	}
+	if err := b.Set("world", "laser", 0, pose(0, &quat.Vec3{})); !errors.Is(err, ParentChangedError) {
+		t.Errorf("Expected ParentChangedError, got %v", err)
+	}
+}
+
+func TestBuffer_Set_ShouldCapHistory(t *testing.T) {
+	b, err := NewBuffer(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k <= 5; k++ {
+		if err := b.Set("world", "base", float64(k), pose(0, &quat.Vec3{X: float64(k)})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := b.Lookup("world", "base", 4.5); err != nil {
+		t.Errorf("Expected lookup inside history, got %v", err)
+	}
+
+	var extrapolation *ExtrapolationError
+	if _, err := b.Lookup("world", "base", 3.5); !errors.As(err, &extrapolation) || extrapolation.Earliest != 4 {
+		t.Errorf("Expected history to start at 4, got %v", err)
+	}
+	if got := b.Frames(); len(got) != 2 || got[0] != "base" || got[1] != "world" {
+		t.Errorf("Wrong frames %v", got)
+	}
+}
+
+func TestNewBuffer_ShouldRejectInvalidCacheTime(t *testing.T) {
+	for _, cacheTime := range []float64{-1, math.NaN()} {
+		if _, err := NewBuffer(cacheTime); !errors.Is(err, CacheTimeError) {
+			t.Errorf("Expected CacheTimeError for %v, got %v", cacheTime, err)
+		}
+	}
+}
+
+func TestBuffer_Set_ShouldKeepNewestSample(t *testing.T) {
+	b, err := NewBuffer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cacheTime := range []float64{0, -1, math.NaN()} {
+		b.CacheTime = cacheTime
+		if err := b.Set("world", "base", 2, pose(0, &quat.Vec3{X: 2})); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := b.Lookup("world", "base", 2)
+		if err != nil {
+			t.Fatalf("Expected newest sample with cache time %v, got %v", cacheTime, err)
+		}
+		if want := pose(0, &quat.Vec3{X: 2}); *got.Q != *want.Q {
+			t.Errorf("Wrong transform with cache time %v. Expected %v, got %v", cacheTime, want, got)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package geodesy converts between WGS-84 geodetic coordinates, the
+// Earth-centered Earth-fixed (ECEF) frame and local East-North-Up (ENU) and
+// North-East-Down (NED) frames.
+//
+// Angles are in radians and distances in meters. A frame rotation named
+// like ENURotation maps vectors given in the local frame to ECEF, i.e. it is
+// the attitude of the local frame in ECEF; its conjugate maps back.
+package geodesy
+
+import (
+	quat "go-quaternions"
+	"math"
+)
+
+const (
+	SemiMajorAxis = 6378137.0
+	Flattening    = 1 / 298.257223563
+)
+
+var (
+	semiMinorAxis             = SemiMajorAxis * (1 - Flattening)
+	eccentricitySquared       = Flattening * (2 - Flattening)
+	secondEccentricitySquared = eccentricitySquared / (1 - eccentricitySquared)
+)
+
+type Geodetic struct {
+	Latitude, Longitude float64
+	// Altitude is the height above the ellipsoid.
+	Altitude float64
+}
+
+func NewGeodeticDeg(latitude, longitude, altitude float64) Geodetic {
+	return Geodetic{Latitude: latitude * math.Pi / 180, Longitude: longitude * math.Pi / 180, Altitude: altitude}
+}
+
+func (g Geodetic) ECEF() *quat.Vec3 {
+	sinLat, cosLat := math.Sincos(g.Latitude)
+	sinLon, cosLon := math.Sincos(g.Longitude)
+	n := SemiMajorAxis / math.Sqrt(1-eccentricitySquared*sinLat*sinLat)
+
+	return &quat.Vec3{
+		X: (n + g.Altitude) * cosLat * cosLon,
+		Y: (n + g.Altitude) * cosLat * sinLon,
+		Z: (n*(1-eccentricitySquared) + g.Altitude) * sinLat,
+	}
+}
+
+// NewGeodeticFromECEF uses Heikkinen's closed form, which is exact to
+// well below a millimeter everywhere except close to the Earth's center.
+func NewGeodeticFromECEF(p *quat.Vec3) Geodetic {
+	a, b, e2 := SemiMajorAxis, semiMinorAxis, eccentricitySquared
+
+	r := math.Hypot(p.X, p.Y)
+	f := 54 * b * b * p.Z * p.Z
+	g := r*r + (1-e2)*p.Z*p.Z - e2*(a*a-b*b)
+	c := e2 * e2 * f * r * r / (g * g * g)
+	s := math.Cbrt(1 + c + math.Sqrt(c*c+2*c))
+	k := s + 1 + 1/s
+	pp := f / (3 * k * k * g * g)
+	q := math.Sqrt(1 + 2*e2*e2*pp)
+	r0 := -pp*e2*r/(1+q) + math.Sqrt(math.Max(0, a*a/2*(1+1/q)-pp*(1-e2)*p.Z*p.Z/(q*(1+q))-pp*r*r/2))
+	u := math.Hypot(r-e2*r0, p.Z)
+	v := math.Sqrt((r-e2*r0)*(r-e2*r0) + (1-e2)*p.Z*p.Z)
+	z0 := b * b * p.Z / (a * v)
+
+	return Geodetic{
+		Latitude:  math.Atan2(p.Z+secondEccentricitySquared*z0, r),
+		Longitude: math.Atan2(p.Y, p.X),
+		Altitude:  u * (1 - b*b/(a*v)),
+	}
+}
+
+// ENURotation returns the attitude of the ENU frame at the given latitude
+// and longitude in ECEF: Rz(λ + π/2) * Rx(π/2 - φ).
+func ENURotation(latitude, longitude float64) *quat.Quaternion {
+	z := axisRotation(&quat.Vec3{Z: 1}, longitude+math.Pi/2)
+	x := axisRotation(&quat.Vec3{X: 1}, math.Pi/2-latitude)
+
+	return z.MulByGrassmann(x)
+}
+
+// NEDRotation returns the attitude of the NED frame in ECEF.
+func NEDRotation(latitude, longitude float64) *quat.Quaternion {
+	return ENURotation(latitude, longitude).MulByGrassmann(enuFromNED())
+}
+
+// ECEFToENU returns the ENU coordinates of an ECEF point relative to
+// origin.
+func ECEFToENU(p *quat.Vec3, origin Geodetic) *quat.Vec3 {
+	return ENURotation(origin.Latitude, origin.Longitude).Conjugate().Compile().Apply(p.Sub(origin.ECEF()))
+}
+
+func ENUToECEF(p *quat.Vec3, origin Geodetic) *quat.Vec3 {
+	return translate(ENURotation(origin.Latitude, origin.Longitude).Compile().Apply(p), origin.ECEF())
+}
+
+func ECEFToNED(p *quat.Vec3, origin Geodetic) *quat.Vec3 {
+	return NEDRotation(origin.Latitude, origin.Longitude).Conjugate().Compile().Apply(p.Sub(origin.ECEF()))
+}
+
+func NEDToECEF(p *quat.Vec3, origin Geodetic) *quat.Vec3 {
+	return translate(NEDRotation(origin.Latitude, origin.Longitude).Compile().Apply(p), origin.ECEF())
+}
+
+// NEDToENUAttitude converts the attitude of a forward-right-down body in a
+// NED frame into the attitude of the same body as forward-left-up in ENU.
+func NEDToENUAttitude(q *quat.Quaternion) *quat.Quaternion {
+	return enuFromNED().MulByGrassmann(q).MulByGrassmann(frdFromFLU())
+}
+
+// ENUToNEDAttitude is the inverse of NEDToENUAttitude.
+func ENUToNEDAttitude(q *quat.Quaternion) *quat.Quaternion {
+	return enuFromNED().Conjugate().MulByGrassmann(q).MulByGrassmann(frdFromFLU().Conjugate())
+}
+
+// enuFromNED maps NED vectors to ENU: a half turn about (1, 1, 0)/√2 that
+// swaps north and east and flips down.
+func enuFromNED() *quat.Quaternion {
+	return quat.NewQuaternionByCoords(0, math.Sqrt2/2, math.Sqrt2/2, 0)
+}
+
+// frdFromFLU maps forward-left-up body vectors to forward-right-down, a
+// half turn about the forward axis.
+func frdFromFLU() *quat.Quaternion {
+	return quat.NewQuaternionByCoords(0, 1, 0, 0)
+}
+
+func axisRotation(axis *quat.Vec3, angle float64) *quat.Quaternion {
+	sin, cos := math.Sincos(angle / 2)
+
+	return quat.NewQuaternionByCoords(cos, sin*axis.X, sin*axis.Y, sin*axis.Z)
+}
+
+func translate(p, t *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{X: p.X + t.X, Y: p.Y + t.Y, Z: p.Z + t.Z}
+}
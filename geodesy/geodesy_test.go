@@ -0,0 +1,140 @@
+package geodesy
+
+import (
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestGeodetic_ECEF(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Geodetic
+		want *quat.Vec3
+	}{
+		{name: "equator", g: NewGeodeticDeg(0, 0, 0), want: &quat.Vec3{X: SemiMajorAxis}},
+		{name: "east", g: NewGeodeticDeg(0, 90, 100), want: &quat.Vec3{Y: SemiMajorAxis + 100}},
+		{name: "north pole", g: NewGeodeticDeg(90, 0, 0), want: &quat.Vec3{Z: 6356752.314245179}},
+		{name: "south pole", g: NewGeodeticDeg(-90, 45, -10), want: &quat.Vec3{Z: -6356742.314245179}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.g.ECEF(); !got.Equals(tt.want, 1e-6) {
+				t.Errorf("Wrong result of ECEF for %+v. Expected %v, got %v", tt.g, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewGeodeticFromECEF_ShouldRoundTrip(t *testing.T) {
+	altitudes := []float64{-400, 0, 8848, 400e3, 20200e3}
+	for n := 0; n < 200; n++ {
+		g := Geodetic{
+			Latitude:  (rand.Float64() - 0.5) * math.Pi,
+			Longitude: (rand.Float64()*2 - 1) * math.Pi,
+			Altitude:  altitudes[n%len(altitudes)],
+		}
+
+		got := NewGeodeticFromECEF(g.ECEF())
+		if math.Abs(got.Latitude-g.Latitude) > 1e-12 || math.Abs(got.Longitude-g.Longitude) > 1e-12 || math.Abs(got.Altitude-g.Altitude) > 1e-6 {
+			t.Errorf("Wrong result of ECEF round trip. Expected %+v, got %+v", g, got)
+		}
+	}
+
+	pole := NewGeodeticFromECEF(NewGeodeticDeg(90, 0, 1000).ECEF())
+	if math.Abs(pole.Latitude-math.Pi/2) > 1e-12 || math.Abs(pole.Altitude-1000) > 1e-6 {
+		t.Errorf("Wrong result at the pole. Expected latitude π/2 and altitude 1000, got %+v", pole)
+	}
+}
+
+func TestENURotation(t *testing.T) {
+	lat, lon := rand.Float64()-0.5, rand.Float64()*6-3
+	r := ENURotation(lat, lon).Compile()
+
+	sinLat, cosLat := math.Sincos(lat)
+	sinLon, cosLon := math.Sincos(lon)
+	tests := []struct {
+		name  string
+		local *quat.Vec3
+		want  *quat.Vec3
+	}{
+		{name: "east", local: &quat.Vec3{X: 1}, want: &quat.Vec3{X: -sinLon, Y: cosLon}},
+		{name: "north", local: &quat.Vec3{Y: 1}, want: &quat.Vec3{X: -sinLat * cosLon, Y: -sinLat * sinLon, Z: cosLat}},
+		{name: "up", local: &quat.Vec3{Z: 1}, want: &quat.Vec3{X: cosLat * cosLon, Y: cosLat * sinLon, Z: sinLat}},
+	}
+	for _, tt := range tests {
+		if got := r.Apply(tt.local); !got.Equals(tt.want, 1e-15) {
+			t.Errorf("Wrong %s axis of ENU. Expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	ned := NEDRotation(lat, lon).Compile()
+	if got := ned.Apply(&quat.Vec3{Z: 1}); !got.Equals(r.Apply(&quat.Vec3{Z: -1}), 1e-15) {
+		t.Errorf("Wrong down axis of NED. Expected %v, got %v", r.Apply(&quat.Vec3{Z: -1}), got)
+	}
+	if got := ned.Apply(&quat.Vec3{X: 1}); !got.Equals(r.Apply(&quat.Vec3{Y: 1}), 1e-15) {
+		t.Errorf("Wrong north axis of NED. Expected %v, got %v", r.Apply(&quat.Vec3{Y: 1}), got)
+	}
+}
+
+func TestLocalFrames(t *testing.T) {
+	origin := NewGeodeticDeg(47.3769, 8.5417, 408)
+
+	above := Geodetic{Latitude: origin.Latitude, Longitude: origin.Longitude, Altitude: origin.Altitude + 100}
+	if got := ECEFToENU(above.ECEF(), origin); !got.Equals(&quat.Vec3{Z: 100}, 1e-8) {
+		t.Errorf("Wrong ENU of a point above the origin. Expected (0, 0, 100), got %v", got)
+	}
+	if got := ECEFToNED(above.ECEF(), origin); !got.Equals(&quat.Vec3{Z: -100}, 1e-8) {
+		t.Errorf("Wrong NED of a point above the origin. Expected (0, 0, -100), got %v", got)
+	}
+
+	p := &quat.Vec3{X: 120, Y: -35, Z: 12}
+	if got := ECEFToENU(ENUToECEF(p, origin), origin); !got.Equals(p, 1e-8) {
+		t.Errorf("Wrong result of ENU round trip. Expected %v, got %v", p, got)
+	}
+	ned := ECEFToNED(ENUToECEF(p, origin), origin)
+	if want := (&quat.Vec3{X: p.Y, Y: p.X, Z: -p.Z}); !ned.Equals(want, 1e-8) {
+		t.Errorf("Wrong result of ENU to NED. Expected %v, got %v", want, ned)
+	}
+	if got := ECEFToNED(NEDToECEF(ned, origin), origin); !got.Equals(ned, 1e-8) {
+		t.Errorf("Wrong result of NED round trip. Expected %v, got %v", ned, got)
+	}
+}
+
+func TestNEDToENUAttitude(t *testing.T) {
+	yaw := func(angle float64) *quat.Quaternion {
+		return axisRotation(&quat.Vec3{Z: 1}, angle)
+	}
+
+	tests := []struct {
+		name string
+		ned  *quat.Quaternion
+		enu  *quat.Quaternion
+	}{
+		// Heading north is yaw 0 in NED and yaw 90° in ENU.
+		{name: "north", ned: yaw(0), enu: yaw(math.Pi / 2)},
+		{name: "east", ned: yaw(math.Pi / 2), enu: yaw(0)},
+		// Heading 30° east of north, NED yaw turns clockwise seen from
+		// above and ENU yaw counterclockwise.
+		{name: "heading 30", ned: yaw(math.Pi / 6), enu: yaw(math.Pi / 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NEDToENUAttitude(tt.ned); !got.EqualsRotation(tt.enu, 1e-15) {
+				t.Errorf("Wrong result of NEDToENUAttitude. Expected %v, got %v", tt.enu, got)
+			}
+			if got := ENUToNEDAttitude(tt.enu); !got.EqualsRotation(tt.ned, 1e-15) {
+				t.Errorf("Wrong result of ENUToNEDAttitude. Expected %v, got %v", tt.ned, got)
+			}
+		})
+	}
+
+	// Pitching the nose up is positive about the FRD right axis; the
+	// forward axis must end up pointing up in ENU.
+	pitchUp := axisRotation(&quat.Vec3{Y: 1}, math.Pi/2)
+	forward := NEDToENUAttitude(pitchUp).Compile().Apply(&quat.Vec3{X: 1})
+	if !forward.Equals(&quat.Vec3{Z: 1}, 1e-15) {
+		t.Errorf("Wrong forward axis after pitching up. Expected (0, 0, 1), got %v", forward)
+	}
+}
@@ -0,0 +1,391 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"math/big"
+	"sort"
+)
+
+// maxFourSquares keeps every intermediate of FourSquares within int64.
+const maxFourSquares = 1 << 30
+
+var (
+	NotHurwitzError       = errors.WithStack(errors.New("Coordinates are neither all integers nor all halves of odd integers"))
+	NumberOutOfRangeError = errors.WithStack(errors.New("Number out of range"))
+)
+
+// LipschitzQuaternion is a quaternion with integer components. Products and
+// norms are not checked for overflow.
+type LipschitzQuaternion struct {
+	W, I, J, K int64
+}
+
+func NewLipschitzQuaternion(W, I, J, K int64) *LipschitzQuaternion {
+	return &LipschitzQuaternion{W: W, I: I, J: J, K: K}
+}
+
+func (q *LipschitzQuaternion) Add(arg *LipschitzQuaternion) *LipschitzQuaternion {
+	return NewLipschitzQuaternion(q.W+arg.W, q.I+arg.I, q.J+arg.J, q.K+arg.K)
+}
+
+func (q *LipschitzQuaternion) Sub(arg *LipschitzQuaternion) *LipschitzQuaternion {
+	return NewLipschitzQuaternion(q.W-arg.W, q.I-arg.I, q.J-arg.J, q.K-arg.K)
+}
+
+func (q *LipschitzQuaternion) MulByGrassmann(arg *LipschitzQuaternion) *LipschitzQuaternion {
+	w, i, j, k := mulCoords(q.W, q.I, q.J, q.K, arg.W, arg.I, arg.J, arg.K)
+
+	return NewLipschitzQuaternion(w, i, j, k)
+}
+
+func (q *LipschitzQuaternion) Conjugate() *LipschitzQuaternion {
+	return NewLipschitzQuaternion(q.W, -q.I, -q.J, -q.K)
+}
+
+// Norm returns the squared length, as Quaternion.Norm does.
+func (q *LipschitzQuaternion) Norm() int64 {
+	return q.W*q.W + q.I*q.I + q.J*q.J + q.K*q.K
+}
+
+func (q *LipschitzQuaternion) Equals(arg *LipschitzQuaternion) bool {
+	return *q == *arg
+}
+
+func (q *LipschitzQuaternion) Hurwitz() *HurwitzQuaternion {
+	return &HurwitzQuaternion{w: 2 * q.W, i: 2 * q.I, j: 2 * q.J, k: 2 * q.K}
+}
+
+func (q *LipschitzQuaternion) Float() *Quaternion {
+	return NewQuaternionByCoords(float64(q.W), float64(q.I), float64(q.J), float64(q.K))
+}
+
+func (q *LipschitzQuaternion) String() string {
+	return fmt.Sprintf("(%d)+(%d)i+(%d)j+(%d)k", q.W, q.I, q.J, q.K)
+}
+
+// HurwitzQuaternion is an element of the Hurwitz order: its components are
+// either all integers or all halves of odd integers. They are stored doubled
+// so both cases stay in int64. Unlike the Lipschitz quaternions the order is
+// Euclidean, which gives division with remainder, greatest common divisors
+// and factorization into primes. Products and norms are not checked for
+// overflow.
+type HurwitzQuaternion struct {
+	w, i, j, k int64
+}
+
+// NewHurwitzQuaternion returns W + Ii + Jj + Kk for integer components.
+func NewHurwitzQuaternion(W, I, J, K int64) *HurwitzQuaternion {
+	return &HurwitzQuaternion{w: 2 * W, i: 2 * I, j: 2 * J, k: 2 * K}
+}
+
+// NewHurwitzQuaternionFromDoubled returns (W + Ii + Jj + Kk)/2, which is a
+// Hurwitz quaternion if the arguments are all even or all odd.
+func NewHurwitzQuaternionFromDoubled(W, I, J, K int64) (*HurwitzQuaternion, error) {
+	q := &HurwitzQuaternion{w: W, i: I, j: J, k: K}
+	if !q.valid() {
+		return nil, errors.Wrapf(NotHurwitzError, "(%d, %d, %d, %d)/2", W, I, J, K)
+	}
+
+	return q, nil
+}
+
+// Doubled returns twice the components.
+func (q *HurwitzQuaternion) Doubled() (W, I, J, K int64) {
+	return q.w, q.i, q.j, q.k
+}
+
+// Lipschitz returns q with integer components, or false if they are halves.
+func (q *HurwitzQuaternion) Lipschitz() (*LipschitzQuaternion, bool) {
+	if q.w&1 != 0 {
+		return nil, false
+	}
+
+	return NewLipschitzQuaternion(q.w/2, q.i/2, q.j/2, q.k/2), true
+}
+
+func (q *HurwitzQuaternion) Add(arg *HurwitzQuaternion) *HurwitzQuaternion {
+	return &HurwitzQuaternion{w: q.w + arg.w, i: q.i + arg.i, j: q.j + arg.j, k: q.k + arg.k}
+}
+
+func (q *HurwitzQuaternion) Sub(arg *HurwitzQuaternion) *HurwitzQuaternion {
+	return &HurwitzQuaternion{w: q.w - arg.w, i: q.i - arg.i, j: q.j - arg.j, k: q.k - arg.k}
+}
+
+func (q *HurwitzQuaternion) MulByGrassmann(arg *HurwitzQuaternion) *HurwitzQuaternion {
+	// The product of doubled components is four times the product, and the
+	// order is closed under multiplication, so the halving is exact.
+	w, i, j, k := mulCoords(q.w, q.i, q.j, q.k, arg.w, arg.i, arg.j, arg.k)
+
+	return &HurwitzQuaternion{w: w / 2, i: i / 2, j: j / 2, k: k / 2}
+}
+
+func (q *HurwitzQuaternion) Conjugate() *HurwitzQuaternion {
+	return &HurwitzQuaternion{w: q.w, i: -q.i, j: -q.j, k: -q.k}
+}
+
+// Norm returns the squared length, which is always an integer.
+func (q *HurwitzQuaternion) Norm() int64 {
+	return (q.w*q.w + q.i*q.i + q.j*q.j + q.k*q.k) / 4
+}
+
+// IsUnit reports whether q is one of the 24 units of the order.
+func (q *HurwitzQuaternion) IsUnit() bool {
+	return q.Norm() == 1
+}
+
+func (q *HurwitzQuaternion) IsZero() bool {
+	return q.w == 0 && q.i == 0 && q.j == 0 && q.k == 0
+}
+
+func (q *HurwitzQuaternion) Equals(arg *HurwitzQuaternion) bool {
+	return *q == *arg
+}
+
+// DivModRight returns quo and rem with q = quo*arg + rem and
+// rem.Norm() < arg.Norm().
+func (q *HurwitzQuaternion) DivModRight(arg *HurwitzQuaternion) (*HurwitzQuaternion, *HurwitzQuaternion, error) {
+	if arg.IsZero() {
+		return nil, nil, AllComponentsEqualsToZeroError
+	}
+
+	quo := nearestHurwitz(q.MulByGrassmann(arg.Conjugate()), arg.Norm(), func(c *HurwitzQuaternion) *HurwitzQuaternion {
+		return q.Sub(c.MulByGrassmann(arg))
+	})
+
+	return quo, q.Sub(quo.MulByGrassmann(arg)), nil
+}
+
+// DivModLeft returns quo and rem with q = arg*quo + rem and
+// rem.Norm() < arg.Norm().
+func (q *HurwitzQuaternion) DivModLeft(arg *HurwitzQuaternion) (*HurwitzQuaternion, *HurwitzQuaternion, error) {
+	if arg.IsZero() {
+		return nil, nil, AllComponentsEqualsToZeroError
+	}
+
+	quo := nearestHurwitz(arg.Conjugate().MulByGrassmann(q), arg.Norm(), func(c *HurwitzQuaternion) *HurwitzQuaternion {
+		return q.Sub(arg.MulByGrassmann(c))
+	})
+
+	return quo, q.Sub(arg.MulByGrassmann(quo)), nil
+}
+
+// RightGCD returns a greatest common right divisor g, so that q = x*g and
+// arg = y*g for Hurwitz quaternions x and y. It is unique up to a unit on
+// the left.
+func (q *HurwitzQuaternion) RightGCD(arg *HurwitzQuaternion) *HurwitzQuaternion {
+	a, b := q, arg
+	for !b.IsZero() {
+		_, rem, _ := a.DivModRight(b)
+		a, b = b, rem
+	}
+
+	return a
+}
+
+// LeftGCD returns a greatest common left divisor g, so that q = g*x and
+// arg = g*y. It is unique up to a unit on the right.
+func (q *HurwitzQuaternion) LeftGCD(arg *HurwitzQuaternion) *HurwitzQuaternion {
+	a, b := q, arg
+	for !b.IsZero() {
+		_, rem, _ := a.DivModLeft(b)
+		a, b = b, rem
+	}
+
+	return a
+}
+
+// Factorize returns Hurwitz primes, each with a rational prime norm, whose
+// product in order is q. The factors of the largest rational integer that
+// divides q come first, as pairs π, π̄; the remaining factors have ascending
+// norms. A unit is returned as itself.
+func (q *HurwitzQuaternion) Factorize() ([]*HurwitzQuaternion, error) {
+	if q.IsZero() {
+		return nil, AllComponentsEqualsToZeroError
+	}
+
+	var factors []*HurwitzQuaternion
+
+	primitive := q
+	for _, p := range primeFactors(q.Norm()) {
+		if divided, ok := primitive.divideByInteger(p); ok {
+			primitive = divided
+			pi := hurwitzPrime(p)
+			factors = append(factors, pi, pi.Conjugate())
+		}
+	}
+
+	// A primitive quaternion has exactly one right divisor of norm p, up to
+	// units, for every prime p of its norm, so the factors can be peeled
+	// off from the right.
+	primes := primeFactors(primitive.Norm())
+	tail := make([]*HurwitzQuaternion, len(primes))
+	for n := len(primes) - 1; n >= 0; n-- {
+		p := primes[n]
+		tail[n] = primitive.RightGCD(NewHurwitzQuaternion(p, 0, 0, 0))
+		primitive, _ = primitive.MulByGrassmann(tail[n].Conjugate()).divideByInteger(p)
+	}
+	factors = append(factors, tail...)
+
+	// What remains is a unit.
+	if len(factors) == 0 {
+		return []*HurwitzQuaternion{primitive}, nil
+	}
+	factors[0] = primitive.MulByGrassmann(factors[0])
+
+	return factors, nil
+}
+
+func (q *HurwitzQuaternion) Float() *Quaternion {
+	return NewQuaternionByCoords(float64(q.w)/2, float64(q.i)/2, float64(q.j)/2, float64(q.k)/2)
+}
+
+func (q *HurwitzQuaternion) String() string {
+	if l, ok := q.Lipschitz(); ok {
+		return l.String()
+	}
+
+	return fmt.Sprintf("(%d/2)+(%d/2)i+(%d/2)j+(%d/2)k", q.w, q.i, q.j, q.k)
+}
+
+func (q *HurwitzQuaternion) valid() bool {
+	parity := q.w & 1
+
+	return q.i&1 == parity && q.j&1 == parity && q.k&1 == parity
+}
+
+// divideByInteger returns q/n if it is a Hurwitz quaternion.
+func (q *HurwitzQuaternion) divideByInteger(n int64) (*HurwitzQuaternion, bool) {
+	if q.w%n != 0 || q.i%n != 0 || q.j%n != 0 || q.k%n != 0 {
+		return nil, false
+	}
+
+	divided := &HurwitzQuaternion{w: q.w / n, i: q.i / n, j: q.j / n, k: q.k / n}
+
+	return divided, divided.valid()
+}
+
+// FourSquares returns non-negative a ≥ b ≥ c ≥ d with a²+b²+c²+d² = n. Each
+// prime factor of n is written as the norm of a Lipschitz quaternion, and
+// the quaternions are multiplied, which multiplies their norms.
+func FourSquares(n int64) ([4]int64, error) {
+	if n < 0 || n > maxFourSquares {
+		return [4]int64{}, errors.Wrapf(NumberOutOfRangeError, "%d is not in [0, %d]", n, maxFourSquares)
+	}
+	if n == 0 {
+		return [4]int64{}, nil
+	}
+
+	product := NewLipschitzQuaternion(1, 0, 0, 0)
+	for _, p := range primeFactors(n) {
+		product = product.MulByGrassmann(lipschitzPrime(p))
+	}
+
+	squares := []int64{abs64(product.W), abs64(product.I), abs64(product.J), abs64(product.K)}
+	sort.Slice(squares, func(a, b int) bool { return squares[a] > squares[b] })
+
+	return [4]int64{squares[0], squares[1], squares[2], squares[3]}, nil
+}
+
+// hurwitzPrime returns a Hurwitz quaternion of prime norm p.
+func hurwitzPrime(p int64) *HurwitzQuaternion {
+	if p == 2 {
+		return NewHurwitzQuaternion(1, 1, 0, 0)
+	}
+
+	// a + bi + j is primitive with norm divisible by p, so its common
+	// divisor with p has norm p.
+	bp := big.NewInt(p)
+	for a := int64(0); ; a++ {
+		x := big.NewInt(-1 - a*a)
+		if b := new(big.Int).ModSqrt(x.Mod(x, bp), bp); b != nil {
+			return NewHurwitzQuaternion(p, 0, 0, 0).RightGCD(NewHurwitzQuaternion(a, b.Int64(), 1, 0))
+		}
+	}
+}
+
+// lipschitzPrime returns a Lipschitz quaternion of prime norm p. A Hurwitz
+// quaternion with half components is moved to integers by a unit factor.
+func lipschitzPrime(p int64) *LipschitzQuaternion {
+	pi := hurwitzPrime(p)
+	for n := int64(0); n < 16; n++ {
+		unit := &HurwitzQuaternion{w: 1 - 2*(n&1), i: 1 - (n & 2), j: 1 - (n&4)/2, k: 1 - (n&8)/4}
+		if l, ok := pi.MulByGrassmann(unit).Lipschitz(); ok {
+			return l
+		}
+	}
+
+	l, _ := pi.Lipschitz()
+
+	return l
+}
+
+// nearestHurwitz returns the Hurwitz quaternion nearest to x/n, taking the
+// nearest one with integer and with half components and keeping the one
+// whose remainder has the smaller norm.
+func nearestHurwitz(x *HurwitzQuaternion, n int64, remainder func(*HurwitzQuaternion) *HurwitzQuaternion) *HurwitzQuaternion {
+	whole := &HurwitzQuaternion{
+		w: 2 * roundDiv(x.w, 2*n),
+		i: 2 * roundDiv(x.i, 2*n),
+		j: 2 * roundDiv(x.j, 2*n),
+		k: 2 * roundDiv(x.k, 2*n),
+	}
+	half := &HurwitzQuaternion{
+		w: 2*roundDiv(x.w-n, 2*n) + 1,
+		i: 2*roundDiv(x.i-n, 2*n) + 1,
+		j: 2*roundDiv(x.j-n, 2*n) + 1,
+		k: 2*roundDiv(x.k-n, 2*n) + 1,
+	}
+
+	if remainder(half).Norm() < remainder(whole).Norm() {
+		return half
+	}
+
+	return whole
+}
+
+func mulCoords(w1, i1, j1, k1, w2, i2, j2, k2 int64) (int64, int64, int64, int64) {
+	return w1*w2 - i1*i2 - j1*j2 - k1*k2,
+		w1*i2 + i1*w2 + j1*k2 - k1*j2,
+		w1*j2 - i1*k2 + j1*w2 + k1*i2,
+		w1*k2 + i1*j2 - j1*i2 + k1*w2
+}
+
+// roundDiv returns the integer nearest to a/b for b > 0.
+func roundDiv(a, b int64) int64 {
+	return floorDiv(2*a+b, 2*b)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+
+	return q
+}
+
+// primeFactors returns the prime factors of n > 0 in ascending order, with
+// multiplicity.
+func primeFactors(n int64) []int64 {
+	var primes []int64
+	for p := int64(2); p*p <= n; p++ {
+		for n%p == 0 {
+			primes = append(primes, p)
+			n /= p
+		}
+	}
+	if n > 1 {
+		primes = append(primes, n)
+	}
+
+	return primes
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
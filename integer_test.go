@@ -0,0 +1,184 @@
+package go_quaternions
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomHurwitzQuaternion(limit int64) *HurwitzQuaternion {
+	parity := rand.Int63n(2)
+	c := [4]int64{}
+	for n := range c {
+		c[n] = 2*(rand.Int63n(2*limit+1)-limit) + parity
+	}
+	q, _ := NewHurwitzQuaternionFromDoubled(c[0], c[1], c[2], c[3])
+
+	return q
+}
+
+func product(factors []*HurwitzQuaternion) *HurwitzQuaternion {
+	p := NewHurwitzQuaternion(1, 0, 0, 0)
+	for _, f := range factors {
+		p = p.MulByGrassmann(f)
+	}
+
+	return p
+}
+
+func TestHurwitzQuaternion_ShouldMatchQuaternion(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		a, b := randomHurwitzQuaternion(100), randomHurwitzQuaternion(100)
+
+		if got, want := a.MulByGrassmann(b).Float(), a.Float().MulByGrassmann(b.Float()); *got != *want {
+			t.Errorf("Wrong result of mul for %v and %v. Expected %v, got %v", a, b, want, got)
+		}
+		if got, want := float64(a.Norm()), a.Float().Norm(); got != want {
+			t.Errorf("Wrong norm of %v. Expected %v, got %v", a, want, got)
+		}
+		if !a.Add(b).Sub(b).Equals(a) {
+			t.Errorf("Wrong result of add and sub for %v and %v", a, b)
+		}
+	}
+
+	l := NewLipschitzQuaternion(1, -2, 3, 4)
+	if got, ok := l.Hurwitz().Lipschitz(); !ok || !got.Equals(l) {
+		t.Errorf("Wrong result of Lipschitz round trip. Expected %v, got %v", l, got)
+	}
+	if got := l.MulByGrassmann(l.Conjugate()); !got.Equals(NewLipschitzQuaternion(30, 0, 0, 0)) {
+		t.Errorf("Wrong result of mul by conjugate. Expected 30, got %v", got)
+	}
+
+	half, _ := NewHurwitzQuaternionFromDoubled(1, 1, -1, 1)
+	if _, ok := half.Lipschitz(); ok || !half.IsUnit() {
+		t.Errorf("Wrong classification of %v", half)
+	}
+	if _, err := NewHurwitzQuaternionFromDoubled(1, 2, 1, 1); err == nil {
+		t.Errorf("Expected error for mixed parity")
+	}
+}
+
+func TestHurwitzQuaternion_DivMod(t *testing.T) {
+	for n := 0; n < 1000; n++ {
+		a, b := randomHurwitzQuaternion(1000), randomHurwitzQuaternion(30)
+		if b.IsZero() {
+			continue
+		}
+
+		quo, rem, err := a.DivModRight(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !quo.MulByGrassmann(b).Add(rem).Equals(a) || rem.Norm() >= b.Norm() {
+			t.Errorf("Wrong result of right division of %v by %v. Got %v and %v", a, b, quo, rem)
+		}
+
+		quo, rem, err = a.DivModLeft(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !b.MulByGrassmann(quo).Add(rem).Equals(a) || rem.Norm() >= b.Norm() {
+			t.Errorf("Wrong result of left division of %v by %v. Got %v and %v", a, b, quo, rem)
+		}
+	}
+
+	if _, _, err := NewHurwitzQuaternion(1, 0, 0, 0).DivModRight(NewHurwitzQuaternion(0, 0, 0, 0)); err == nil {
+		t.Errorf("Expected error for division by zero")
+	}
+}
+
+func TestHurwitzQuaternion_GCD(t *testing.T) {
+	for n := 0; n < 200; n++ {
+		g, x, y := randomHurwitzQuaternion(10), randomHurwitzQuaternion(10), randomHurwitzQuaternion(10)
+		if g.IsZero() || x.IsZero() || y.IsZero() {
+			continue
+		}
+
+		a, b := x.MulByGrassmann(g), y.MulByGrassmann(g)
+		right := a.RightGCD(b)
+		for _, q := range []*HurwitzQuaternion{a, b} {
+			if _, rem, _ := q.DivModRight(right); !rem.IsZero() {
+				t.Errorf("Wrong right gcd of %v and %v. %v does not divide %v", a, b, right, q)
+			}
+		}
+		if _, rem, _ := right.DivModRight(g); !rem.IsZero() {
+			t.Errorf("Wrong right gcd of %v and %v. Common divisor %v does not divide %v", a, b, g, right)
+		}
+
+		a, b = g.MulByGrassmann(x), g.MulByGrassmann(y)
+		left := a.LeftGCD(b)
+		for _, q := range []*HurwitzQuaternion{a, b} {
+			if _, rem, _ := q.DivModLeft(left); !rem.IsZero() {
+				t.Errorf("Wrong left gcd of %v and %v. %v does not divide %v", a, b, left, q)
+			}
+		}
+		if _, rem, _ := left.DivModLeft(g); !rem.IsZero() {
+			t.Errorf("Wrong left gcd of %v and %v. Common divisor %v does not divide %v", a, b, g, left)
+		}
+	}
+}
+
+func TestHurwitzQuaternion_Factorize(t *testing.T) {
+	tests := []*HurwitzQuaternion{
+		NewHurwitzQuaternion(1, 0, 0, 0),
+		NewHurwitzQuaternion(0, 0, 0, -1),
+		NewHurwitzQuaternion(2, 0, 0, 0),
+		NewHurwitzQuaternion(1, 1, 1, 1),
+		NewHurwitzQuaternion(12, 0, 0, 0),
+		NewHurwitzQuaternion(6, 3, -9, 12),
+	}
+	for n := 0; n < 200; n++ {
+		tests = append(tests, randomHurwitzQuaternion(50))
+	}
+
+	for _, q := range tests {
+		if q.IsZero() {
+			continue
+		}
+		factors, err := q.Factorize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := product(factors); !got.Equals(q) {
+			t.Errorf("Wrong factorization of %v. Product of %v is %v", q, factors, got)
+		}
+
+		if len(factors) == 1 && factors[0].IsUnit() {
+			continue
+		}
+		for _, f := range factors {
+			if p := primeFactors(f.Norm()); len(p) != 1 {
+				t.Errorf("Wrong factorization of %v. Norm of %v is not prime", q, f)
+			}
+		}
+	}
+
+	if _, err := NewHurwitzQuaternion(0, 0, 0, 0).Factorize(); err == nil {
+		t.Errorf("Expected error for factorizing zero")
+	}
+}
+
+func TestFourSquares(t *testing.T) {
+	tests := []int64{0, 1, 2, 3, 7, 15, 28, 96, 1 << 20, 999983, 1000000007, 1073741789, maxFourSquares}
+	for n := 0; n < 200; n++ {
+		tests = append(tests, rand.Int63n(maxFourSquares))
+	}
+
+	for _, n := range tests {
+		got, err := FourSquares(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got[0]*got[0]+got[1]*got[1]+got[2]*got[2]+got[3]*got[3] != n {
+			t.Errorf("Wrong four squares of %d. Got %v", n, got)
+		}
+		if got[0] < got[1] || got[1] < got[2] || got[2] < got[3] || got[3] < 0 {
+			t.Errorf("Wrong order of four squares of %d. Got %v", n, got)
+		}
+	}
+
+	for _, n := range []int64{-1, maxFourSquares + 1} {
+		if _, err := FourSquares(n); err == nil {
+			t.Errorf("Expected error for four squares of %d", n)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package kinematics computes forward kinematics of serial chains with
+// dual quaternions.
+//
+// A chain is a sequence of joints, each contributing the transform from the
+// previous link frame to its own. Link poses are BQuaternion products, so the
+// pose of link k maps points in its frame to the base frame.
+package kinematics
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+)
+
+var (
+	WrongJointCountError = errors.WithStack(errors.New("Wrong number of joint values"))
+	ZeroAxisError        = errors.WithStack(errors.New("Joint axis is zero"))
+)
+
+type JointType int
+
+const (
+	Revolute JointType = iota
+	Prismatic
+)
+
+type Convention int
+
+const (
+	// StandardDH is Rz(θ)*Tz(d)*Tx(a)*Rx(α); the joint moves about or
+	// along z of the previous frame.
+	StandardDH Convention = iota
+	// ModifiedDH (Craig) is Rx(α)*Tx(a)*Rz(θ)*Tz(d); the joint moves about
+	// or along z of its own frame.
+	ModifiedDH
+	// AxisJoint is Origin followed by a motion about or along Axis.
+	AxisJoint
+)
+
+type Joint struct {
+	Name       string
+	Type       JointType
+	Convention Convention
+
+	// DH parameters. Theta and D are offsets added to the joint value of
+	// revolute and prismatic joints respectively.
+	A, Alpha, D, Theta float64
+
+	// Origin and unit Axis of an AxisJoint.
+	Origin *quat.BQuaternion
+	Axis   *quat.Vec3
+
+	// Min and Max bound the joint value when Limited is set.
+	Limited  bool
+	Min, Max float64
+}
+
+func NewStandardDH(jointType JointType, a, alpha, d, theta float64) *Joint {
+	return &Joint{Type: jointType, Convention: StandardDH, A: a, Alpha: alpha, D: d, Theta: theta}
+}
+
+func NewModifiedDH(jointType JointType, a, alpha, d, theta float64) *Joint {
+	return &Joint{Type: jointType, Convention: ModifiedDH, A: a, Alpha: alpha, D: d, Theta: theta}
+}
+
+// NewAxisJoint normalizes axis, so a prismatic joint moves by exactly its
+// joint value. A nil origin is the identity.
+func NewAxisJoint(jointType JointType, origin *quat.BQuaternion, axis *quat.Vec3) (*Joint, error) {
+	if axis == nil || axis.Length() == 0 {
+		return nil, ZeroAxisError
+	}
+	if origin == nil {
+		origin = identity()
+	}
+
+	return &Joint{Type: jointType, Convention: AxisJoint, Origin: origin, Axis: scaled(axis, 1/axis.Length())}, nil
+}
+
+// Transform returns the transform from the previous link frame to the frame
+// of this joint at joint value q.
+func (j *Joint) Transform(q float64) *quat.BQuaternion {
+	pre, motion, post := j.split(q)
+
+	return pre.Mul(motion).Mul(post)
+}
+
+// split separates the fixed transform before the joint axis, the joint
+// motion and the fixed transform after it.
+func (j *Joint) split(q float64) (*quat.BQuaternion, *quat.BQuaternion, *quat.BQuaternion) {
+	theta, d := j.Theta, j.D
+	if j.Type == Revolute {
+		theta += q
+	} else {
+		d += q
+	}
+
+	switch j.Convention {
+	case ModifiedDH:
+		return rotationX(j.Alpha).Mul(translation(&quat.Vec3{X: j.A})), rotationZ(theta).Mul(translation(&quat.Vec3{Z: d})), identity()
+	case AxisJoint:
+		motion := translation(scaled(j.Axis, q))
+		if j.Type == Revolute {
+			motion = rotation(j.Axis, q)
+		}
+		return j.Origin, motion, identity()
+	}
+
+	return identity(), rotationZ(theta).Mul(translation(&quat.Vec3{Z: d})), translation(&quat.Vec3{X: j.A}).Mul(rotationX(j.Alpha))
+}
+
+// SetLimits bounds the joint value and returns the joint.
+func (j *Joint) SetLimits(min, max float64) *Joint {
+	j.Limited, j.Min, j.Max = true, min, max
+
+	return j
+}
+
+// Clamp returns q restricted to the joint limits.
+func (j *Joint) Clamp(q float64) float64 {
+	if !j.Limited {
+		return q
+	}
+
+	return math.Max(j.Min, math.Min(j.Max, q))
+}
+
+func (j *Joint) axis() *quat.Vec3 {
+	if j.Convention == AxisJoint {
+		return j.Axis
+	}
+
+	return &quat.Vec3{Z: 1}
+}
+
+type Chain struct {
+	Joints []*Joint
+	// Base places the chain in the world and Tool offsets the end effector
+	// from the last link; both may be nil.
+	Base, Tool *quat.BQuaternion
+}
+
+func NewChain(joints ...*Joint) *Chain {
+	return &Chain{Joints: joints}
+}
+
+// Poses returns the pose of every link frame for joint values q.
+func (c *Chain) Poses(q []float64) ([]*quat.BQuaternion, error) {
+	if len(q) != len(c.Joints) {
+		return nil, errors.Wrapf(WrongJointCountError, "expected %d, got %d", len(c.Joints), len(q))
+	}
+
+	poses := make([]*quat.BQuaternion, len(q))
+	pose := c.base()
+	for k, j := range c.Joints {
+		pose = pose.Mul(j.Transform(q[k]))
+		poses[k] = pose
+	}
+
+	return poses, nil
+}
+
+// EndEffector returns the tool pose for joint values q.
+func (c *Chain) EndEffector(q []float64) (*quat.BQuaternion, error) {
+	poses, err := c.Poses(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.endEffector(poses), nil
+}
+
+// Jacobian returns the geometric Jacobian, one column per joint. Column k is
+// [v; ω], the linear and angular velocity of the end effector in the base
+// frame for a unit velocity of joint k.
+func (c *Chain) Jacobian(q []float64) ([]lie.Vec6, error) {
+	poses, err := c.Poses(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.jacobian(q, poses), nil
+}
+
+func (c *Chain) jacobian(q []float64, poses []*quat.BQuaternion) []lie.Vec6 {
+	end := c.endEffector(poses).Translation()
+
+	columns := make([]lie.Vec6, len(c.Joints))
+	for k, axis := range c.axes(q, poses) {
+		if c.Joints[k].Type == Prismatic {
+			columns[k] = lie.NewVec6(axis.direction, &quat.Vec3{})
+			continue
+		}
+		columns[k] = lie.NewVec6(cross(axis.direction, end.Sub(axis.origin)), axis.direction)
+	}
+
+	return columns
+}
+
+// jointAxis is the line a joint moves about or along, in the base frame.
+type jointAxis struct {
+	origin, direction *quat.Vec3
+}
+
+func (c *Chain) axes(q []float64, poses []*quat.BQuaternion) []jointAxis {
+	axes := make([]jointAxis, len(c.Joints))
+	previous := c.base()
+	for k, j := range c.Joints {
+		pre, _, _ := j.split(q[k])
+		frame := previous.Mul(pre)
+		previous = poses[k]
+
+		axes[k] = jointAxis{origin: frame.Translation(), direction: frame.P.Compile().Apply(j.axis())}
+	}
+
+	return axes
+}
+
+func (c *Chain) base() *quat.BQuaternion {
+	if c.Base != nil {
+		return c.Base
+	}
+
+	return identity()
+}
+
+func (c *Chain) endEffector(poses []*quat.BQuaternion) *quat.BQuaternion {
+	end := c.base()
+	if len(poses) > 0 {
+		end = poses[len(poses)-1]
+	}
+	if c.Tool != nil {
+		end = end.Mul(c.Tool)
+	}
+
+	return end
+}
+
+func identity() *quat.BQuaternion {
+	return quat.NewBQuaternion(quat.NewQuaternionByCoords(1, 0, 0, 0), quat.NewQuaternionByCoords(0, 0, 0, 0))
+}
+
+func translation(t *quat.Vec3) *quat.BQuaternion {
+	return quat.NewRigidBQuaternion(quat.NewQuaternionByCoords(1, 0, 0, 0), t)
+}
+
+func rotation(axis *quat.Vec3, angle float64) *quat.BQuaternion {
+	s := math.Sin(angle/2) / axis.Length()
+
+	return quat.NewRigidBQuaternion(quat.NewQuaternionByCoords(math.Cos(angle/2), s*axis.X, s*axis.Y, s*axis.Z), &quat.Vec3{})
+}
+
+func rotationX(angle float64) *quat.BQuaternion {
+	return rotation(&quat.Vec3{X: 1}, angle)
+}
+
+func rotationZ(angle float64) *quat.BQuaternion {
+	return rotation(&quat.Vec3{Z: 1}, angle)
+}
+
+func scaled(v *quat.Vec3, s float64) *quat.Vec3 {
+	return &quat.Vec3{X: s * v.X, Y: s * v.Y, Z: s * v.Z}
+}
+
+func cross(a, b *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{
+		X: a.Y*b.Z - a.Z*b.Y,
+		Y: a.Z*b.X - a.X*b.Z,
+		Z: a.X*b.Y - a.Y*b.X,
+	}
+}
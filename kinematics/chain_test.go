@@ -0,0 +1,165 @@
+package kinematics
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const jacobianEpsilon = 1e-6
+
+func mustAxisJoint(jointType JointType, origin *quat.BQuaternion, axis *quat.Vec3) *Joint {
+	j, err := NewAxisJoint(jointType, origin, axis)
+	if err != nil {
+		panic(err)
+	}
+
+	return j
+}
+
+func planarArms() map[string]*Chain {
+	modified := NewChain(
+		NewModifiedDH(Revolute, 0, 0, 0, 0),
+		NewModifiedDH(Revolute, 1, 0, 0, 0),
+	)
+	modified.Tool = translation(&quat.Vec3{X: 1})
+
+	axis := NewChain(
+		mustAxisJoint(Revolute, identity(), &quat.Vec3{Z: 1}),
+		mustAxisJoint(Revolute, translation(&quat.Vec3{X: 1}), &quat.Vec3{Z: 1}),
+	)
+	axis.Tool = translation(&quat.Vec3{X: 1})
+
+	return map[string]*Chain{
+		"standard": NewChain(
+			NewStandardDH(Revolute, 1, 0, 0, 0),
+			NewStandardDH(Revolute, 1, 0, 0, 0),
+		),
+		"modified": modified,
+		"axis":     axis,
+	}
+}
+
+func TestChain_EndEffector_PlanarArm(t *testing.T) {
+	tests := []struct {
+		q    []float64
+		want *quat.Vec3
+	}{
+		{q: []float64{0, 0}, want: &quat.Vec3{X: 2}},
+		{q: []float64{math.Pi / 2, -math.Pi / 2}, want: &quat.Vec3{X: 1, Y: 1}},
+		{q: []float64{math.Pi / 2, 0}, want: &quat.Vec3{Y: 2}},
+		{q: []float64{0, math.Pi}, want: &quat.Vec3{}},
+	}
+	for name, chain := range planarArms() {
+		for _, tt := range tests {
+			end, err := chain.EndEffector(tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := end.Translation(); !got.Equals(tt.want, 1e-12) {
+				t.Errorf("Wrong result of %s EndEffector for %v. Expected %v, got %v", name, tt.q, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestChain_Poses_ShouldRejectWrongJointCount(t *testing.T) {
+	chain := planarArms()["standard"]
+	if _, err := chain.Poses([]float64{0}); err == nil {
+		t.Errorf("Expected error for wrong number of joint values")
+	}
+}
+
+func TestChain_Poses_Prismatic(t *testing.T) {
+	chain := NewChain(
+		NewStandardDH(Revolute, 0, -math.Pi/2, 0.5, 0),
+		NewStandardDH(Prismatic, 0, 0, 0, 0),
+	)
+	poses, err := chain.Poses([]float64{math.Pi / 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first joint turns the arm towards Y and tips z of link 1 onto
+	// the horizontal plane, so the slide moves along -X.
+	want := &quat.Vec3{X: -2, Z: 0.5}
+	if got := poses[1].Translation(); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong result of Poses. Expected %v, got %v", want, got)
+	}
+}
+
+func randomChain() *Chain {
+	joints := make([]*Joint, 0, 6)
+	for k := 0; k < 2; k++ {
+		joints = append(joints,
+			NewStandardDH(Revolute, rand.Float64(), rand.Float64()*math.Pi, rand.Float64(), rand.Float64()),
+			NewModifiedDH(Prismatic, rand.Float64(), rand.Float64()*math.Pi, rand.Float64(), rand.Float64()),
+		)
+
+		axis := &quat.Vec3{X: rand.Float64() - 0.5, Y: rand.Float64() - 0.5, Z: rand.Float64() - 0.5}
+		axis = scaled(axis, 1/axis.Length())
+		origin := rotation(&quat.Vec3{X: rand.Float64(), Y: 1}, rand.Float64()).Mul(translation(&quat.Vec3{X: rand.Float64(), Z: rand.Float64()}))
+		joints = append(joints, mustAxisJoint(JointType(k), origin, axis))
+	}
+
+	chain := NewChain(joints...)
+	chain.Base = rotation(&quat.Vec3{Z: 1}, rand.Float64()).Mul(translation(&quat.Vec3{X: 1}))
+	chain.Tool = translation(&quat.Vec3{X: 0.2, Z: 0.1})
+
+	return chain
+}
+
+func TestNewAxisJoint(t *testing.T) {
+	j, err := NewAxisJoint(Prismatic, nil, &quat.Vec3{Y: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &quat.Vec3{Y: 0.5}
+	if got := j.Transform(0.5).Translation(); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong travel of non-unit prismatic axis. Expected %v, got %v", want, got)
+	}
+
+	if _, err := NewAxisJoint(Revolute, identity(), &quat.Vec3{}); !errors.Is(err, ZeroAxisError) {
+		t.Errorf("Expected ZeroAxisError, got %v", err)
+	}
+}
+
+func TestChain_Jacobian_ShouldMatchNumericalDerivative(t *testing.T) {
+	for n := 0; n < 10; n++ {
+		chain := randomChain()
+		q := make([]float64, len(chain.Joints))
+		for k := range q {
+			q[k] = rand.Float64()*2 - 1
+		}
+
+		columns, err := chain.Jacobian(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		const h = 1e-6
+		for k := range q {
+			plus := append([]float64(nil), q...)
+			minus := append([]float64(nil), q...)
+			plus[k] += h
+			minus[k] -= h
+
+			endPlus, _ := chain.EndEffector(plus)
+			endMinus, _ := chain.EndEffector(minus)
+
+			v := scaled(endPlus.Translation().Sub(endMinus.Translation()), 1/(2*h))
+			omega, err := lie.SO3Log(endPlus.P.MulByGrassmann(endMinus.P.Conjugate()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			omega = scaled(omega, 1/(2*h))
+
+			if !columns[k].Rho().Equals(v, jacobianEpsilon) || !columns[k].Phi().Equals(omega, jacobianEpsilon) {
+				t.Errorf("Wrong Jacobian column %d. Expected [%v; %v], got [%v; %v]", k, v, omega, columns[k].Rho(), columns[k].Phi())
+			}
+		}
+	}
+}
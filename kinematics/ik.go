@@ -0,0 +1,347 @@
+package kinematics
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+)
+
+const minDamping = 1e-6
+
+var SingularSystemError = errors.WithStack(errors.New("Singular linear system"))
+
+type Solver int
+
+const (
+	// DampedLeastSquares steps by J'(JJ' + λ²I)⁻¹e on the geometric
+	// Jacobian.
+	DampedLeastSquares Solver = iota
+	// CCD sweeps the joints from the tip to the base, solving each one in
+	// closed form.
+	CCD
+	// FABRIK moves the joint origins along the chain as points and maps
+	// them back to joint values, turning each joint towards the target
+	// orientation as well for full poses.
+	FABRIK
+)
+
+type IKOptions struct {
+	Solver        Solver
+	MaxIterations int
+	// PositionTolerance and RotationTolerance (rad) are the errors at
+	// which the target counts as reached.
+	PositionTolerance, RotationTolerance float64
+	// PositionOnly ignores the target orientation.
+	PositionOnly bool
+	// Damping scales λ of DampedLeastSquares, which is Damping times the
+	// error norm (with a small floor).
+	Damping float64
+}
+
+func DefaultIKOptions() IKOptions {
+	return IKOptions{
+		Solver:            DampedLeastSquares,
+		MaxIterations:     200,
+		PositionTolerance: 1e-6,
+		RotationTolerance: 1e-6,
+		Damping:           0.5,
+	}
+}
+
+type IKResult struct {
+	Joints     []float64
+	Iterations int
+	// PositionError and RotationError are measured at Joints.
+	PositionError, RotationError float64
+	Converged                    bool
+}
+
+// Solve searches joint values that move the end effector to target,
+// starting from initial. An unreachable target is not an error; the result
+// reports the closest joint values found with Converged unset.
+func (c *Chain) Solve(target *quat.BQuaternion, initial []float64, opts IKOptions) (*IKResult, error) {
+	if len(initial) != len(c.Joints) {
+		return nil, errors.Wrapf(WrongJointCountError, "expected %d, got %d", len(c.Joints), len(initial))
+	}
+
+	q := make([]float64, len(initial))
+	for k, j := range c.Joints {
+		q[k] = j.Clamp(initial[k])
+	}
+
+	result := &IKResult{Joints: q}
+	for {
+		poses, err := c.Poses(q)
+		if err != nil {
+			return nil, err
+		}
+		result.PositionError, result.RotationError, err = c.poseError(target, poses)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.PositionError <= opts.PositionTolerance && (opts.PositionOnly || result.RotationError <= opts.RotationTolerance) {
+			result.Converged = true
+			return result, nil
+		}
+		if result.Iterations == opts.MaxIterations {
+			return result, nil
+		}
+		result.Iterations++
+
+		switch opts.Solver {
+		case CCD:
+			c.ccdSweep(target, q, opts.PositionOnly)
+		case FABRIK:
+			c.fabrikIteration(target, q, opts.PositionOnly)
+		default:
+			if err := c.dlsStep(target, q, poses, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (c *Chain) poseError(target *quat.BQuaternion, poses []*quat.BQuaternion) (float64, float64, error) {
+	end := c.endEffector(poses)
+
+	omega, err := lie.SO3Log(target.P.MulByGrassmann(end.P.Conjugate()))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return target.Translation().Sub(end.Translation()).Length(), omega.Length(), nil
+}
+
+func (c *Chain) dlsStep(target *quat.BQuaternion, q []float64, poses []*quat.BQuaternion, opts IKOptions) error {
+	end := c.endEffector(poses)
+	columns := c.jacobian(q, poses)
+
+	dp := target.Translation().Sub(end.Translation())
+	e := []float64{dp.X, dp.Y, dp.Z}
+	if !opts.PositionOnly {
+		omega, err := lie.SO3Log(target.P.MulByGrassmann(end.P.Conjugate()))
+		if err != nil {
+			return err
+		}
+		e = append(e, omega.X, omega.Y, omega.Z)
+	}
+
+	// The damping shrinks with the error, which keeps the steps bounded
+	// far from the target and recovers Gauss-Newton convergence near it.
+	// JJ' + λ²I is only as large as the task space, so the step needs a
+	// 3x3 or 6x6 solve whatever the number of joints.
+	damping := opts.Damping * opts.Damping * (dot6(e, e) + minDamping)
+	m := len(e)
+	a := make([][]float64, m)
+	for r := range a {
+		a[r] = make([]float64, m)
+		for s := range a[r] {
+			for _, column := range columns {
+				a[r][s] += column[r] * column[s]
+			}
+		}
+		a[r][r] += damping
+	}
+
+	y, err := solveLinear(a, e)
+	if err != nil {
+		return err
+	}
+
+	for k, column := range columns {
+		var step float64
+		for r := range y {
+			step += column[r] * y[r]
+		}
+		q[k] = c.Joints[k].Clamp(q[k] + step)
+	}
+
+	return nil
+}
+
+// ccdSweep updates every joint once, from the tip to the base. A revolute
+// joint turns by the angle that best aligns the end effector position and,
+// unless positionOnly is set, its frame axes with those of the target.
+func (c *Chain) ccdSweep(target *quat.BQuaternion, q []float64, positionOnly bool) {
+	targetPosition := target.Translation()
+	targetFrame := target.P.Compile().R
+
+	for k := len(c.Joints) - 1; k >= 0; k-- {
+		poses, _ := c.Poses(q)
+		end := c.endEffector(poses)
+		endPosition := end.Translation()
+		axis := c.axes(q, poses)[k]
+
+		j := c.Joints[k]
+		if j.Type == Prismatic {
+			q[k] = j.Clamp(q[k] + dot(axis.direction, targetPosition.Sub(endPosition)))
+			continue
+		}
+
+		u := []*quat.Vec3{endPosition.Sub(axis.origin)}
+		v := []*quat.Vec3{targetPosition.Sub(axis.origin)}
+		if !positionOnly {
+			endFrame := end.P.Compile().R
+			for n := 0; n < 3; n++ {
+				u = append(u, column(endFrame, n))
+				v = append(v, column(targetFrame, n))
+			}
+		}
+
+		q[k] = j.Clamp(q[k] + bestAngle(axis.direction, u, v))
+	}
+}
+
+// fabrikIteration runs a backward and a forward FABRIK pass over the joint
+// origins and the end effector, then turns each joint in turn so the points
+// beyond it follow their new positions.
+func (c *Chain) fabrikIteration(target *quat.BQuaternion, q []float64, positionOnly bool) {
+	targetFrame := target.P.Compile().R
+
+	poses, _ := c.Poses(q)
+	axes := c.axes(q, poses)
+
+	n := len(c.Joints)
+	points := make([]*quat.Vec3, n+1)
+	for k, axis := range axes {
+		points[k] = axis.origin
+	}
+	points[n] = c.endEffector(poses).Translation()
+
+	lengths := make([]float64, n)
+	for k := range lengths {
+		lengths[k] = points[k+1].Sub(points[k]).Length()
+	}
+
+	root := points[0]
+	points[n] = target.Translation()
+	for k := n - 1; k >= 0; k-- {
+		points[k] = towards(points[k+1], points[k], lengths[k])
+	}
+	points[0] = root
+	for k := 0; k < n; k++ {
+		points[k+1] = towards(points[k], points[k+1], lengths[k])
+	}
+
+	for k, j := range c.Joints {
+		poses, _ = c.Poses(q)
+		axes = c.axes(q, poses)
+		axis := axes[k]
+
+		current := make([]*quat.Vec3, 0, n-k)
+		for _, a := range axes[k+1:] {
+			current = append(current, a.origin)
+		}
+		end := c.endEffector(poses)
+		current = append(current, end.Translation())
+		wanted := points[k+1:]
+
+		if j.Type == Prismatic {
+			var step float64
+			for m := range current {
+				step += dot(axis.direction, wanted[m].Sub(current[m]))
+			}
+			q[k] = j.Clamp(q[k] + step/float64(len(current)))
+			continue
+		}
+
+		// Every point beyond the joint takes part, since the next one
+		// may lie on the joint axis.
+		u := make([]*quat.Vec3, len(current))
+		v := make([]*quat.Vec3, len(current))
+		for m := range current {
+			u[m], v[m] = current[m].Sub(axis.origin), wanted[m].Sub(axis.origin)
+		}
+		if !positionOnly {
+			endFrame := end.P.Compile().R
+			for n := 0; n < 3; n++ {
+				u = append(u, column(endFrame, n))
+				v = append(v, column(targetFrame, n))
+			}
+		}
+
+		q[k] = j.Clamp(q[k] + bestAngle(axis.direction, u, v))
+	}
+}
+
+// bestAngle returns the rotation about the unit axis that brings the vectors
+// u closest to v in the least-squares sense.
+func bestAngle(axis *quat.Vec3, u, v []*quat.Vec3) float64 {
+	var sin, cos float64
+	for n := range u {
+		sin += dot(axis, cross(u[n], v[n]))
+		cos += dot(u[n], v[n]) - dot(axis, u[n])*dot(axis, v[n])
+	}
+
+	return math.Atan2(sin, cos)
+}
+
+// towards returns the point at distance length from anchor in the
+// direction of p, or anchor itself if p coincides with it.
+func towards(anchor, p *quat.Vec3, length float64) *quat.Vec3 {
+	d := p.Sub(anchor)
+	l := d.Length()
+	if l == 0 {
+		return anchor
+	}
+	d = scaled(d, length/l)
+
+	return &quat.Vec3{X: anchor.X + d.X, Y: anchor.Y + d.Y, Z: anchor.Z + d.Z}
+}
+
+// solveLinear solves a*x = b by Gaussian elimination with partial pivoting.
+// Both arguments are overwritten.
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if a[pivot][col] == 0 {
+			return nil, SingularSystemError
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for r := col + 1; r < n; r++ {
+			f := a[r][col] / a[col][col]
+			for s := col; s < n; s++ {
+				a[r][s] -= f * a[col][s]
+			}
+			b[r] -= f * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for r := n - 1; r >= 0; r-- {
+		s := b[r]
+		for c := r + 1; c < n; c++ {
+			s -= a[r][c] * x[c]
+		}
+		x[r] = s / a[r][r]
+	}
+
+	return x, nil
+}
+
+func column(m [3][3]float64, n int) *quat.Vec3 {
+	return &quat.Vec3{X: m[0][n], Y: m[1][n], Z: m[2][n]}
+}
+
+func dot6(a, b []float64) float64 {
+	var s float64
+	for n := range a {
+		s += a[n] * b[n]
+	}
+
+	return s
+}
+
+func dot(a, b *quat.Vec3) float64 {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
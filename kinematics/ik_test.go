@@ -0,0 +1,143 @@
+package kinematics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func puma() *Chain {
+	return NewChain(
+		NewStandardDH(Revolute, 0, math.Pi/2, 0, 0),
+		NewStandardDH(Revolute, 0.4318, 0, 0, 0),
+		NewStandardDH(Revolute, 0.0203, -math.Pi/2, 0.15005, 0),
+		NewStandardDH(Revolute, 0, math.Pi/2, 0.4318, 0),
+		NewStandardDH(Revolute, 0, -math.Pi/2, 0, 0),
+		NewStandardDH(Revolute, 0, 0, 0, 0),
+	)
+}
+
+func randomJoints(n int, spread float64) []float64 {
+	q := make([]float64, n)
+	for k := range q {
+		q[k] = (rand.Float64()*2 - 1) * spread
+	}
+
+	return q
+}
+
+func TestChain_Solve(t *testing.T) {
+	tests := []struct {
+		name         string
+		solver       Solver
+		positionOnly bool
+		iterations   int
+		tolerance    float64
+		// improves relaxes convergence to a tenfold reduction of the
+		// summed error, as full poses need many CCD sweeps near a wrist.
+		improves bool
+	}{
+		{name: "damped least squares", solver: DampedLeastSquares, iterations: 500, tolerance: 1e-7},
+		{name: "damped least squares position", solver: DampedLeastSquares, positionOnly: true, iterations: 500, tolerance: 1e-7},
+		{name: "ccd", solver: CCD, iterations: 500, tolerance: 1e-5, improves: true},
+		{name: "ccd position", solver: CCD, positionOnly: true, iterations: 2000, tolerance: 1e-3},
+		{name: "fabrik", solver: FABRIK, iterations: 500, tolerance: 1e-5, improves: true},
+		{name: "fabrik position", solver: FABRIK, positionOnly: true, iterations: 2000, tolerance: 1e-3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := puma()
+			for n := 0; n < 5; n++ {
+				// Keep away from the wrist singularity at q5 = 0.
+				goal := randomJoints(6, 1)
+				goal[4] = math.Copysign(0.3+math.Abs(goal[4])*0.7, goal[4])
+				target, _ := chain.EndEffector(goal)
+
+				initial := make([]float64, 6)
+				for k := range initial {
+					initial[k] = goal[k] + (rand.Float64()*2-1)*0.3
+				}
+
+				opts := DefaultIKOptions()
+				opts.Solver = tt.solver
+				opts.PositionOnly = tt.positionOnly
+				opts.MaxIterations = tt.iterations
+				opts.PositionTolerance, opts.RotationTolerance = tt.tolerance, tt.tolerance
+
+				result, err := chain.Solve(target, initial, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.improves {
+					opts.MaxIterations = 0
+					start, _ := chain.Solve(target, initial, opts)
+					if result.PositionError+result.RotationError > (start.PositionError+start.RotationError)/10 {
+						t.Errorf("Expected error reduction from %v and %v, got %v and %v",
+							start.PositionError, start.RotationError, result.PositionError, result.RotationError)
+					}
+					continue
+				}
+				if !result.Converged {
+					t.Errorf("Expected convergence to %v from %v, got position error %v and rotation error %v after %d iterations",
+						goal, initial, result.PositionError, result.RotationError, result.Iterations)
+					continue
+				}
+
+				end, _ := chain.EndEffector(result.Joints)
+				if d := end.Translation().Sub(target.Translation()).Length(); d > tt.tolerance {
+					t.Errorf("Wrong result of Solve. Expected position %v, got %v", target.Translation(), end.Translation())
+				}
+			}
+		})
+	}
+}
+
+func TestChain_Solve_ShouldRespectJointLimits(t *testing.T) {
+	chain := NewChain(
+		NewStandardDH(Revolute, 1, 0, 0, 0).SetLimits(-0.5, 0.5),
+		NewStandardDH(Revolute, 1, 0, 0, 0).SetLimits(0, math.Pi/2),
+	)
+
+	// The target needs the first joint at π/2, beyond its limit.
+	target, _ := NewChain(NewStandardDH(Revolute, 1, 0, 0, 0), NewStandardDH(Revolute, 1, 0, 0, 0)).EndEffector([]float64{math.Pi / 2, 0.3})
+
+	for _, solver := range []Solver{DampedLeastSquares, CCD, FABRIK} {
+		opts := DefaultIKOptions()
+		opts.Solver = solver
+		opts.PositionOnly = true
+
+		result, err := chain.Solve(target, []float64{2, -1}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Converged {
+			t.Errorf("Expected no convergence with solver %d, got %v", solver, result.Joints)
+		}
+		if result.Iterations != opts.MaxIterations {
+			t.Errorf("Wrong number of iterations with solver %d. Expected %d, got %d", solver, opts.MaxIterations, result.Iterations)
+		}
+		for k, j := range chain.Joints {
+			if result.Joints[k] < j.Min || result.Joints[k] > j.Max {
+				t.Errorf("Joint %d out of limits with solver %d: %v", k, solver, result.Joints[k])
+			}
+		}
+	}
+}
+
+func TestChain_Solve_ShouldReportReachedTarget(t *testing.T) {
+	chain := puma()
+	q := randomJoints(6, 1)
+	target, _ := chain.EndEffector(q)
+
+	result, err := chain.Solve(target, q, DefaultIKOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged || result.Iterations != 0 {
+		t.Errorf("Expected convergence without iterations, got %d iterations", result.Iterations)
+	}
+
+	if _, err := chain.Solve(target, q[:3], DefaultIKOptions()); err == nil {
+		t.Errorf("Expected error for wrong number of joint values")
+	}
+}
@@ -0,0 +1,16 @@
+// Package lie implements the SO(3) and SE(3) Lie group operators on top of
+// Quaternion and BQuaternion.
+//
+// Conventions:
+//
+//   - A rotation q acts on a point as q*v*q' (Vec3.RotateRad) and products
+//     compose right to left, so q1*q2 applies q2 first.
+//   - The SO(3) tangent is the rotation vector φ; Exp(φ) rotates by |φ|
+//     around φ/|φ|. Log returns |φ| in [0, π].
+//   - A pose T = (R, t) maps x to R*x + t and is stored as the BQuaternion
+//     NewRigidBQuaternion(R, t). The SE(3) tangent is ξ = [ρ; φ], translation
+//     first, with Exp(ξ) = (Exp(φ), Jl(φ)*ρ).
+//   - Perturbations: Exp(x+δ) ≈ Exp(x)*Exp(Jr(x)*δ) ≈ Exp(Jl(x)*δ)*Exp(x),
+//     and Jl(x) = Jr(-x).
+//   - Adjoint: X*Exp(ξ)*X⁻¹ = Exp(Ad(X)*ξ).
+package lie
@@ -0,0 +1,134 @@
+package lie
+
+import quat "go-quaternions"
+
+type Mat3 [3][3]float64
+
+type Mat4 [4][4]float64
+
+type Vec6 [6]float64
+
+type Mat6 [6][6]float64
+
+func Identity3() Mat3 {
+	return Mat3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+func Identity6() Mat6 {
+	var m Mat6
+	for n := range m {
+		m[n][n] = 1
+	}
+
+	return m
+}
+
+func (m Mat3) Add(arg Mat3) Mat3 {
+	for r := range m {
+		for c := range m[r] {
+			m[r][c] += arg[r][c]
+		}
+	}
+
+	return m
+}
+
+func (m Mat3) Scale(s float64) Mat3 {
+	for r := range m {
+		for c := range m[r] {
+			m[r][c] *= s
+		}
+	}
+
+	return m
+}
+
+func (m Mat3) Mul(arg Mat3) Mat3 {
+	var res Mat3
+	for r := range res {
+		for c := range res[r] {
+			res[r][c] = m[r][0]*arg[0][c] + m[r][1]*arg[1][c] + m[r][2]*arg[2][c]
+		}
+	}
+
+	return res
+}
+
+func (m Mat3) MulVec(v *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{
+		X: m[0][0]*v.X + m[0][1]*v.Y + m[0][2]*v.Z,
+		Y: m[1][0]*v.X + m[1][1]*v.Y + m[1][2]*v.Z,
+		Z: m[2][0]*v.X + m[2][1]*v.Y + m[2][2]*v.Z,
+	}
+}
+
+func (m Mat3) Transpose() Mat3 {
+	for r := range m {
+		for c := r + 1; c < 3; c++ {
+			m[r][c], m[c][r] = m[c][r], m[r][c]
+		}
+	}
+
+	return m
+}
+
+func (m Mat6) Mul(arg Mat6) Mat6 {
+	var res Mat6
+	for r := range res {
+		for c := range res[r] {
+			for k := 0; k < 6; k++ {
+				res[r][c] += m[r][k] * arg[k][c]
+			}
+		}
+	}
+
+	return res
+}
+
+func (m Mat6) MulVec(v Vec6) Vec6 {
+	var res Vec6
+	for r := range res {
+		for c := range v {
+			res[r] += m[r][c] * v[c]
+		}
+	}
+
+	return res
+}
+
+func (m Mat6) Transpose() Mat6 {
+	for r := range m {
+		for c := r + 1; c < 6; c++ {
+			m[r][c], m[c][r] = m[c][r], m[r][c]
+		}
+	}
+
+	return m
+}
+
+// block assembles a 6x6 matrix from 3x3 blocks.
+func block(a, b, c, d Mat3) Mat6 {
+	var m Mat6
+	for r := 0; r < 3; r++ {
+		for k := 0; k < 3; k++ {
+			m[r][k] = a[r][k]
+			m[r][k+3] = b[r][k]
+			m[r+3][k] = c[r][k]
+			m[r+3][k+3] = d[r][k]
+		}
+	}
+
+	return m
+}
+
+func (v Vec6) Rho() *quat.Vec3 {
+	return &quat.Vec3{X: v[0], Y: v[1], Z: v[2]}
+}
+
+func (v Vec6) Phi() *quat.Vec3 {
+	return &quat.Vec3{X: v[3], Y: v[4], Z: v[5]}
+}
+
+func NewVec6(rho, phi *quat.Vec3) Vec6 {
+	return Vec6{rho.X, rho.Y, rho.Z, phi.X, phi.Y, phi.Z}
+}
@@ -0,0 +1,98 @@
+package lie
+
+import (
+	quat "go-quaternions"
+	"math"
+)
+
+func SE3Exp(xi Vec6) *quat.BQuaternion {
+	phi := xi.Phi()
+
+	return quat.NewRigidBQuaternion(SO3Exp(phi), SO3LeftJacobian(phi).MulVec(xi.Rho()))
+}
+
+func SE3Log(bq *quat.BQuaternion) (Vec6, error) {
+	phi, err := SO3Log(bq.P)
+	if err != nil {
+		return Vec6{}, err
+	}
+
+	return NewVec6(SO3LeftJacobianInverse(phi).MulVec(bq.Translation()), phi), nil
+}
+
+// SE3Hat returns the 4x4 twist matrix [φ^ ρ; 0 0].
+func SE3Hat(xi Vec6) Mat4 {
+	h := SO3Hat(xi.Phi())
+
+	return Mat4{
+		{h[0][0], h[0][1], h[0][2], xi[0]},
+		{h[1][0], h[1][1], h[1][2], xi[1]},
+		{h[2][0], h[2][1], h[2][2], xi[2]},
+		{0, 0, 0, 0},
+	}
+}
+
+func SE3Vee(m Mat4) Vec6 {
+	return Vec6{m[0][3], m[1][3], m[2][3], m[2][1], m[0][2], m[1][0]}
+}
+
+// SE3Adjoint returns [R t^R; 0 R] for the pose bq.
+func SE3Adjoint(bq *quat.BQuaternion) Mat6 {
+	r := SO3Matrix(bq.P)
+
+	return block(r, SO3Hat(bq.Translation()).Mul(r), Mat3{}, r)
+}
+
+func SE3LeftJacobian(xi Vec6) Mat6 {
+	j := SO3LeftJacobian(xi.Phi())
+
+	return block(j, se3Q(xi), Mat3{}, j)
+}
+
+func SE3LeftJacobianInverse(xi Vec6) Mat6 {
+	inv := SO3LeftJacobianInverse(xi.Phi())
+
+	return block(inv, inv.Mul(se3Q(xi)).Mul(inv).Scale(-1), Mat3{}, inv)
+}
+
+func SE3RightJacobian(xi Vec6) Mat6 {
+	return SE3LeftJacobian(negate6(xi))
+}
+
+func SE3RightJacobianInverse(xi Vec6) Mat6 {
+	return SE3LeftJacobianInverse(negate6(xi))
+}
+
+// se3Q is the upper right block of the SE(3) left Jacobian (Barfoot,
+// "State Estimation for Robotics", eq. 7.86).
+func se3Q(xi Vec6) Mat3 {
+	phi := xi.Phi()
+	theta := phi.Length()
+	t2 := theta * theta
+
+	a, b, c := 1.0/6-t2/120, 1.0/24-t2/720, 1.0/120-t2/2520
+	if theta >= smallAngle {
+		sin, cos := math.Sincos(theta)
+		a = (theta - sin) / (t2 * theta)
+		b = (t2 + 2*cos - 2) / (2 * t2 * t2)
+		c = (2*theta - 3*sin + theta*cos) / (2 * t2 * t2 * theta)
+	}
+
+	p, r := SO3Hat(phi), SO3Hat(xi.Rho())
+	pr, rp := p.Mul(r), r.Mul(p)
+	prp := pr.Mul(p)
+	pp := p.Mul(p)
+
+	return r.Scale(0.5).
+		Add(pr.Add(rp).Add(prp).Scale(a)).
+		Add(pp.Mul(r).Add(rp.Mul(p)).Add(prp.Scale(-3)).Scale(b)).
+		Add(prp.Mul(p).Add(pp.Mul(rp)).Scale(c))
+}
+
+func negate6(xi Vec6) Vec6 {
+	for n := range xi {
+		xi[n] = -xi[n]
+	}
+
+	return xi
+}
@@ -0,0 +1,168 @@
+package lie
+
+import (
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomVec6(scale float64) Vec6 {
+	var v Vec6
+	for n := range v {
+		v[n] = (2*rand.Float64() - 1) * scale
+	}
+
+	return v
+}
+
+func equals6(a, b Mat6, eps float64) bool {
+	for r := range a {
+		for c := range a[r] {
+			if math.Abs(a[r][c]-b[r][c]) > eps {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func equalsVec6(a, b Vec6, eps float64) bool {
+	for n := range a {
+		if math.Abs(a[n]-b[n]) > eps {
+			return false
+		}
+	}
+
+	return true
+}
+
+func numericJacobian6(t *testing.T, f func(d Vec6) *quat.BQuaternion) Mat6 {
+	var m Mat6
+	for k := 0; k < 6; k++ {
+		var d Vec6
+		d[k] = derivativeStep
+		plus, err := SE3Log(f(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d[k] = -derivativeStep
+		minus, err := SE3Log(f(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for r := range m {
+			m[r][k] = (plus[r] - minus[r]) / (2 * derivativeStep)
+		}
+	}
+
+	return m
+}
+
+func add6(a, b Vec6) Vec6 {
+	for n := range a {
+		a[n] += b[n]
+	}
+
+	return a
+}
+
+func TestSE3_ExpLog(t *testing.T) {
+	for _, scale := range []float64{1e-6, 1, 1.7} {
+		xi := randomVec6(scale)
+
+		got, err := SE3Log(SE3Exp(xi))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equalsVec6(got, xi, 1e-12) {
+			t.Errorf("Wrong result of Log(Exp(%v)), got %v", xi, got)
+		}
+
+		if SE3Vee(SE3Hat(xi)) != xi {
+			t.Errorf("Wrong result of Vee(Hat(%v))", xi)
+		}
+	}
+}
+
+func TestSE3_ExpShouldMatchMatrixExponential(t *testing.T) {
+	xi := randomVec6(1)
+	h := SE3Hat(xi)
+
+	// exp of the 4x4 twist matrix by its Taylor series.
+	var exp, term Mat4
+	for n := 0; n < 4; n++ {
+		exp[n][n], term[n][n] = 1, 1
+	}
+	for k := 1; k < 30; k++ {
+		var next Mat4
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 4; c++ {
+				for m := 0; m < 4; m++ {
+					next[r][c] += term[r][m] * h[m][c] / float64(k)
+				}
+			}
+		}
+		term = next
+		for r := 0; r < 4; r++ {
+			for c := 0; c < 4; c++ {
+				exp[r][c] += term[r][c]
+			}
+		}
+	}
+
+	op := SE3Exp(xi).Compile()
+	for r := 0; r < 3; r++ {
+		for c := 0; c < 3; c++ {
+			if math.Abs(op.R[r][c]-exp[r][c]) > 1e-12 {
+				t.Errorf("Wrong rotation at %v,%v. Expected %v, got %v", r, c, exp[r][c], op.R[r][c])
+			}
+		}
+	}
+	if !op.T.Equals(&quat.Vec3{X: exp[0][3], Y: exp[1][3], Z: exp[2][3]}, 1e-12) {
+		t.Errorf("Wrong translation. Expected %v, got %v", exp, op.T)
+	}
+}
+
+func TestSE3_Adjoint(t *testing.T) {
+	x := SE3Exp(randomVec6(2))
+	xi := randomVec6(1)
+
+	got, err := SE3Log(x.Mul(SE3Exp(xi)).Mul(x.ComplexConjugate()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := SE3Adjoint(x).MulVec(xi); !equalsVec6(got, want, 1e-12) {
+		t.Errorf("Wrong adjoint. Expected %v, got %v", want, got)
+	}
+}
+
+func TestSE3_JacobiansShouldMatchNumericalDifferentiation(t *testing.T) {
+	for _, scale := range []float64{1e-5, 0.5, 1.5} {
+		xi := randomVec6(scale)
+		inv := SE3Exp(xi).ComplexConjugate()
+
+		right := numericJacobian6(t, func(d Vec6) *quat.BQuaternion {
+			return inv.Mul(SE3Exp(add6(xi, d)))
+		})
+		if got := SE3RightJacobian(xi); !equals6(got, right, 1e-8) {
+			t.Errorf("Wrong right Jacobian for %v. Expected %v, got %v", xi, right, got)
+		}
+
+		left := numericJacobian6(t, func(d Vec6) *quat.BQuaternion {
+			return SE3Exp(add6(xi, d)).Mul(inv)
+		})
+		if got := SE3LeftJacobian(xi); !equals6(got, left, 1e-8) {
+			t.Errorf("Wrong left Jacobian for %v. Expected %v, got %v", xi, left, got)
+		}
+
+		if got := SE3LeftJacobian(xi).Mul(SE3LeftJacobianInverse(xi)); !equals6(got, Identity6(), 1e-12) {
+			t.Errorf("Wrong left Jacobian inverse for %v, product %v", xi, got)
+		}
+		if got := SE3RightJacobian(xi).Mul(SE3RightJacobianInverse(xi)); !equals6(got, Identity6(), 1e-12) {
+			t.Errorf("Wrong right Jacobian inverse for %v, product %v", xi, got)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package lie
+
+import (
+	quat "go-quaternions"
+	"math"
+)
+
+// smallAngle is the angle below which the closed forms are replaced by
+// their Taylor expansions.
+const smallAngle = 1e-4
+
+func SO3Exp(phi *quat.Vec3) *quat.Quaternion {
+	theta := phi.Length()
+
+	var s float64
+	if theta < smallAngle {
+		s = 0.5 - theta*theta/48
+	} else {
+		s = math.Sin(theta/2) / theta
+	}
+
+	return quat.NewQuaternionByCoords(math.Cos(theta/2), s*phi.X, s*phi.Y, s*phi.Z)
+}
+
+// SO3Log returns the rotation vector of q, which need not be normalized.
+func SO3Log(q *quat.Quaternion) (*quat.Vec3, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if normQ.W < 0 {
+		normQ = normQ.MulByNumber(-1)
+	}
+
+	n := math.Sqrt(normQ.I*normQ.I + normQ.J*normQ.J + normQ.K*normQ.K)
+
+	var s float64
+	if n < smallAngle {
+		s = 2 / normQ.W * (1 - n*n/(3*normQ.W*normQ.W))
+	} else {
+		s = 2 * math.Atan2(n, normQ.W) / n
+	}
+
+	return &quat.Vec3{X: s * normQ.I, Y: s * normQ.J, Z: s * normQ.K}, nil
+}
+
+func SO3Hat(phi *quat.Vec3) Mat3 {
+	return Mat3{
+		{0, -phi.Z, phi.Y},
+		{phi.Z, 0, -phi.X},
+		{-phi.Y, phi.X, 0},
+	}
+}
+
+func SO3Vee(m Mat3) *quat.Vec3 {
+	return &quat.Vec3{X: m[2][1], Y: m[0][2], Z: m[1][0]}
+}
+
+// SO3Matrix is the rotation matrix of a unit quaternion.
+func SO3Matrix(q *quat.Quaternion) Mat3 {
+	return q.Compile().R
+}
+
+func SO3Adjoint(q *quat.Quaternion) Mat3 {
+	return SO3Matrix(q)
+}
+
+func SO3LeftJacobian(phi *quat.Vec3) Mat3 {
+	theta := phi.Length()
+	a, b := 0.5-theta*theta/24, 1.0/6-theta*theta/120
+	if theta >= smallAngle {
+		a = (1 - math.Cos(theta)) / (theta * theta)
+		b = (theta - math.Sin(theta)) / (theta * theta * theta)
+	}
+
+	h := SO3Hat(phi)
+	return Identity3().Add(h.Scale(a)).Add(h.Mul(h).Scale(b))
+}
+
+func SO3LeftJacobianInverse(phi *quat.Vec3) Mat3 {
+	theta := phi.Length()
+	b := 1.0/12 + theta*theta/720
+	if theta >= smallAngle {
+		b = 1/(theta*theta) - (1+math.Cos(theta))/(2*theta*math.Sin(theta))
+	}
+
+	h := SO3Hat(phi)
+	return Identity3().Add(h.Scale(-0.5)).Add(h.Mul(h).Scale(b))
+}
+
+func SO3RightJacobian(phi *quat.Vec3) Mat3 {
+	return SO3LeftJacobian(negate(phi))
+}
+
+func SO3RightJacobianInverse(phi *quat.Vec3) Mat3 {
+	return SO3LeftJacobianInverse(negate(phi))
+}
+
+func negate(v *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{X: -v.X, Y: -v.Y, Z: -v.Z}
+}
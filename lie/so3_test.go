@@ -0,0 +1,146 @@
+package lie
+
+import (
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+const derivativeStep = 1e-6
+
+func randomVec3(scale float64) *quat.Vec3 {
+	return &quat.Vec3{
+		X: (2*rand.Float64() - 1) * scale,
+		Y: (2*rand.Float64() - 1) * scale,
+		Z: (2*rand.Float64() - 1) * scale,
+	}
+}
+
+func basis3(k int, h float64) *quat.Vec3 {
+	v := [3]float64{}
+	v[k] = h
+	return &quat.Vec3{X: v[0], Y: v[1], Z: v[2]}
+}
+
+func add3(a, b *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{X: a.X + b.X, Y: a.Y + b.Y, Z: a.Z + b.Z}
+}
+
+func equals3(a, b Mat3, eps float64) bool {
+	for r := range a {
+		for c := range a[r] {
+			if math.Abs(a[r][c]-b[r][c]) > eps {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// numericJacobian3 differentiates f at zero by central differences.
+func numericJacobian3(t *testing.T, f func(d *quat.Vec3) *quat.Quaternion) Mat3 {
+	var m Mat3
+	for k := 0; k < 3; k++ {
+		plus, err := SO3Log(f(basis3(k, derivativeStep)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		minus, err := SO3Log(f(basis3(k, -derivativeStep)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		col := plus.Sub(minus)
+		m[0][k] = col.X / (2 * derivativeStep)
+		m[1][k] = col.Y / (2 * derivativeStep)
+		m[2][k] = col.Z / (2 * derivativeStep)
+	}
+
+	return m
+}
+
+func TestSO3_ExpLog(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale float64
+		eps   float64
+	}{
+		{name: "small angles", scale: 1e-6, eps: 1e-18},
+		{name: "moderate angles", scale: 1, eps: 1e-12},
+		{name: "large angles", scale: 1.8, eps: 1e-12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phi := randomVec3(tt.scale)
+
+			q := SO3Exp(phi)
+			if math.Abs(q.Norm()-1) > 1e-15 {
+				t.Errorf("Expected unit quaternion, got %v with norm %v", q, q.Norm())
+			}
+
+			got, err := SO3Log(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equals(phi, tt.eps) {
+				t.Errorf("Wrong result of Log(Exp(%v)), got %v", phi, got)
+			}
+
+			v := randomVec3(1)
+			rotated, err := v.RotateRad(phi, phi.Length())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.scale > 1e-3 && !q.Compile().Apply(v).Equals(rotated, 1e-12) {
+				t.Errorf("Exp(%v) does not match RotateRad", phi)
+			}
+
+			if *SO3Vee(SO3Hat(phi)) != *phi {
+				t.Errorf("Wrong result of Vee(Hat(%v))", phi)
+			}
+		})
+	}
+}
+
+func TestSO3_Adjoint(t *testing.T) {
+	x := SO3Exp(randomVec3(2))
+	phi := randomVec3(1)
+
+	got, err := SO3Log(x.MulByGrassmann(SO3Exp(phi)).MulByGrassmann(x.Conjugate()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := SO3Adjoint(x).MulVec(phi); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong adjoint. Expected %v, got %v", want, got)
+	}
+}
+
+func TestSO3_JacobiansShouldMatchNumericalDifferentiation(t *testing.T) {
+	for _, scale := range []float64{1e-5, 0.5, 2} {
+		phi := randomVec3(scale)
+		inv := SO3Exp(phi).Conjugate()
+
+		right := numericJacobian3(t, func(d *quat.Vec3) *quat.Quaternion {
+			return inv.MulByGrassmann(SO3Exp(add3(phi, d)))
+		})
+		if got := SO3RightJacobian(phi); !equals3(got, right, 1e-8) {
+			t.Errorf("Wrong right Jacobian for %v. Expected %v, got %v", phi, right, got)
+		}
+
+		left := numericJacobian3(t, func(d *quat.Vec3) *quat.Quaternion {
+			return SO3Exp(add3(phi, d)).MulByGrassmann(inv)
+		})
+		if got := SO3LeftJacobian(phi); !equals3(got, left, 1e-8) {
+			t.Errorf("Wrong left Jacobian for %v. Expected %v, got %v", phi, left, got)
+		}
+
+		if got := SO3LeftJacobian(phi).Mul(SO3LeftJacobianInverse(phi)); !equals3(got, Identity3(), 1e-12) {
+			t.Errorf("Wrong left Jacobian inverse for %v, product %v", phi, got)
+		}
+		if got := SO3RightJacobian(phi).Mul(SO3RightJacobianInverse(phi)); !equals3(got, Identity3(), 1e-12) {
+			t.Errorf("Wrong right Jacobian inverse for %v, product %v", phi, got)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package go_quaternions
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"math"
+)
+
+var (
+	WrongBinaryLengthError = errors.WithStack(errors.New("Wrong length of binary data"))
+	WrongJSONLengthError   = errors.WithStack(errors.New("Wrong number of components in JSON array"))
+	MissingJSONPartError   = errors.WithStack(errors.New("Missing or null part of dual quaternion in JSON"))
+)
+
+// QuaternionWXYZ is a Quaternion written to JSON as the array [w, x, y, z].
+// Like the other array types below it is chosen per field, for example
+// Orientation QuaternionXYZW in a config struct, and converts to and from
+// Quaternion without copying.
+type QuaternionWXYZ Quaternion
+
+// QuaternionXYZW is a Quaternion written to JSON as the array [x, y, z, w],
+// the order used by ROS, Eigen's coeffs() and most trajectory formats.
+type QuaternionXYZW Quaternion
+
+// BQuaternionWXYZ is a BQuaternion written to JSON as [P, Q] with both parts
+// as QuaternionWXYZ arrays.
+type BQuaternionWXYZ BQuaternion
+
+// BQuaternionXYZW is a BQuaternion written to JSON as [P, Q] with both parts
+// as QuaternionXYZW arrays.
+type BQuaternionXYZW BQuaternion
+
+// Vec3Array is a Vec3 written to JSON as the array [x, y, z].
+type Vec3Array Vec3
+
+type quaternionJSON struct {
+	W float64 `json:"w"`
+	I float64 `json:"i"`
+	J float64 `json:"j"`
+	K float64 `json:"k"`
+}
+
+type bquaternionJSON struct {
+	P json.RawMessage `json:"p"`
+	Q json.RawMessage `json:"q"`
+}
+
+type vec3JSON struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// MarshalJSON writes the object {"w":..,"i":..,"j":..,"k":..}. It has a value
+// receiver, as have all Marshal methods here, so that values and pointers
+// encode alike, also in structs passed by value.
+func (q Quaternion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quaternionJSON{W: q.W, I: q.I, J: q.J, K: q.K})
+}
+
+// UnmarshalJSON reads an object, or an array in w, x, y, z order. Every
+// array type accepts objects as well.
+func (q *Quaternion) UnmarshalJSON(data []byte) error {
+	return unmarshalQuaternionJSON(data, q, false)
+}
+
+func (q QuaternionWXYZ) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{q.W, q.I, q.J, q.K})
+}
+
+func (q *QuaternionWXYZ) UnmarshalJSON(data []byte) error {
+	return unmarshalQuaternionJSON(data, q.Quaternion(), false)
+}
+
+func (q *QuaternionWXYZ) Quaternion() *Quaternion {
+	return (*Quaternion)(q)
+}
+
+func (q QuaternionXYZW) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{q.I, q.J, q.K, q.W})
+}
+
+func (q *QuaternionXYZW) UnmarshalJSON(data []byte) error {
+	return unmarshalQuaternionJSON(data, q.Quaternion(), true)
+}
+
+func (q *QuaternionXYZW) Quaternion() *Quaternion {
+	return (*Quaternion)(q)
+}
+
+// MarshalJSON writes the object {"p":..,"q":..} with Quaternion objects. A
+// nil part is written as zero here and in the other Marshal methods, so the
+// zero BQuaternion encodes and decodes like any other.
+func (bq BQuaternion) MarshalJSON() ([]byte, error) {
+	p, q := bq.parts()
+
+	return json.Marshal(struct {
+		P *Quaternion `json:"p"`
+		Q *Quaternion `json:"q"`
+	}{P: p, Q: q})
+}
+
+// UnmarshalJSON reads an object or an array [P, Q], with the parts read as
+// by Quaternion. A missing or null part is an error.
+func (bq *BQuaternion) UnmarshalJSON(data []byte) error {
+	return unmarshalBQuaternionJSON(data, bq, false)
+}
+
+func (bq BQuaternionWXYZ) MarshalJSON() ([]byte, error) {
+	p, q := BQuaternion(bq).parts()
+
+	return json.Marshal([2]*QuaternionWXYZ{(*QuaternionWXYZ)(p), (*QuaternionWXYZ)(q)})
+}
+
+func (bq *BQuaternionWXYZ) UnmarshalJSON(data []byte) error {
+	return unmarshalBQuaternionJSON(data, bq.BQuaternion(), false)
+}
+
+func (bq *BQuaternionWXYZ) BQuaternion() *BQuaternion {
+	return (*BQuaternion)(bq)
+}
+
+func (bq BQuaternionXYZW) MarshalJSON() ([]byte, error) {
+	p, q := BQuaternion(bq).parts()
+
+	return json.Marshal([2]*QuaternionXYZW{(*QuaternionXYZW)(p), (*QuaternionXYZW)(q)})
+}
+
+func (bq *BQuaternionXYZW) UnmarshalJSON(data []byte) error {
+	return unmarshalBQuaternionJSON(data, bq.BQuaternion(), true)
+}
+
+func (bq *BQuaternionXYZW) BQuaternion() *BQuaternion {
+	return (*BQuaternion)(bq)
+}
+
+// MarshalJSON writes the object {"x":..,"y":..,"z":..}.
+func (v Vec3) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vec3JSON{X: v.X, Y: v.Y, Z: v.Z})
+}
+
+// UnmarshalJSON reads an object or an array [x, y, z].
+func (v *Vec3) UnmarshalJSON(data []byte) error {
+	if isJSONArray(data) {
+		var c []float64
+		if err := json.Unmarshal(data, &c); err != nil {
+			return err
+		}
+		if len(c) != 3 {
+			return WrongJSONLengthError
+		}
+
+		v.X, v.Y, v.Z = c[0], c[1], c[2]
+		return nil
+	}
+
+	var o vec3JSON
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	v.X, v.Y, v.Z = o.X, o.Y, o.Z
+
+	return nil
+}
+
+func (v Vec3Array) MarshalJSON() ([]byte, error) {
+	return json.Marshal([3]float64{v.X, v.Y, v.Z})
+}
+
+func (v *Vec3Array) UnmarshalJSON(data []byte) error {
+	return v.Vec3().UnmarshalJSON(data)
+}
+
+func (v *Vec3Array) Vec3() *Vec3 {
+	return (*Vec3)(v)
+}
+
+func (q Quaternion) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Quaternion) UnmarshalText(text []byte) error {
+	parsed, err := ParseQuaternion(string(text))
+	if err != nil {
+		return err
+	}
+	*q = *parsed
+
+	return nil
+}
+
+func (bq BQuaternion) MarshalText() ([]byte, error) {
+	p, q := bq.parts()
+
+	return []byte((&BQuaternion{P: p, Q: q}).String()), nil
+}
+
+func (bq *BQuaternion) UnmarshalText(text []byte) error {
+	p, q := &Quaternion{}, &Quaternion{}
+
+	_, err := fmt.Sscanf(
+		string(text),
+		"{\n(%g)+(%g)i+(%g)j+(%g)k,\n(%g)+(%g)i+(%g)j+(%g)k\n}",
+		&p.W, &p.I, &p.J, &p.K,
+		&q.W, &q.I, &q.J, &q.K,
+	)
+	if err != nil {
+		return err
+	}
+	bq.P, bq.Q = p, q
+
+	return nil
+}
+
+func (v Vec3) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Vec3) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "(%g, %g, %g)", &v.X, &v.Y, &v.Z)
+	return err
+}
+
+// MarshalBinary writes W, I, J, K as little-endian IEEE 754 doubles.
+func (q Quaternion) MarshalBinary() ([]byte, error) {
+	return appendFloats(make([]byte, 0, 32), q.W, q.I, q.J, q.K), nil
+}
+
+func (q *Quaternion) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return WrongBinaryLengthError
+	}
+	c := readFloats(data)
+	q.W, q.I, q.J, q.K = c[0], c[1], c[2], c[3]
+
+	return nil
+}
+
+// MarshalBinary writes P followed by Q, 64 bytes in total.
+func (bq BQuaternion) MarshalBinary() ([]byte, error) {
+	p, q := bq.parts()
+
+	return appendFloats(make([]byte, 0, 64),
+		p.W, p.I, p.J, p.K,
+		q.W, q.I, q.J, q.K,
+	), nil
+}
+
+func (bq *BQuaternion) UnmarshalBinary(data []byte) error {
+	if len(data) != 64 {
+		return WrongBinaryLengthError
+	}
+	c := readFloats(data)
+	bq.P = NewQuaternionByCoords(c[0], c[1], c[2], c[3])
+	bq.Q = NewQuaternionByCoords(c[4], c[5], c[6], c[7])
+
+	return nil
+}
+
+func (v Vec3) MarshalBinary() ([]byte, error) {
+	return appendFloats(make([]byte, 0, 24), v.X, v.Y, v.Z), nil
+}
+
+func (v *Vec3) UnmarshalBinary(data []byte) error {
+	if len(data) != 24 {
+		return WrongBinaryLengthError
+	}
+	c := readFloats(data)
+	v.X, v.Y, v.Z = c[0], c[1], c[2]
+
+	return nil
+}
+
+func unmarshalQuaternionJSON(data []byte, q *Quaternion, scalarLast bool) error {
+	if isJSONArray(data) {
+		var c []float64
+		if err := json.Unmarshal(data, &c); err != nil {
+			return err
+		}
+		if len(c) != 4 {
+			return WrongJSONLengthError
+		}
+
+		if scalarLast {
+			q.W, q.I, q.J, q.K = c[3], c[0], c[1], c[2]
+		} else {
+			q.W, q.I, q.J, q.K = c[0], c[1], c[2], c[3]
+		}
+		return nil
+	}
+
+	var o quaternionJSON
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+	q.W, q.I, q.J, q.K = o.W, o.I, o.J, o.K
+
+	return nil
+}
+
+func unmarshalBQuaternionJSON(data []byte, bq *BQuaternion, scalarLast bool) error {
+	var p, q json.RawMessage
+	if isJSONArray(data) {
+		var c []json.RawMessage
+		if err := json.Unmarshal(data, &c); err != nil {
+			return err
+		}
+		if len(c) != 2 {
+			return WrongJSONLengthError
+		}
+		p, q = c[0], c[1]
+	} else {
+		var o bquaternionJSON
+		if err := json.Unmarshal(data, &o); err != nil {
+			return err
+		}
+		p, q = o.P, o.Q
+	}
+
+	parts := [2]*Quaternion{{}, {}}
+	for n, raw := range [2]json.RawMessage{p, q} {
+		if len(raw) == 0 || string(raw) == "null" {
+			return MissingJSONPartError
+		}
+		if err := unmarshalQuaternionJSON(raw, parts[n], scalarLast); err != nil {
+			return err
+		}
+	}
+	bq.P, bq.Q = parts[0], parts[1]
+
+	return nil
+}
+
+// parts returns P and Q with nil replaced by the zero quaternion.
+func (bq BQuaternion) parts() (*Quaternion, *Quaternion) {
+	p, q := bq.P, bq.Q
+	if p == nil {
+		p = &Quaternion{}
+	}
+	if q == nil {
+		q = &Quaternion{}
+	}
+
+	return p, q
+}
+
+func isJSONArray(data []byte) bool {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c == '['
+	}
+
+	return false
+}
+
+func appendFloats(b []byte, values ...float64) []byte {
+	var buf [8]byte
+	for _, f := range values {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+		b = append(b, buf[:]...)
+	}
+
+	return b
+}
+
+func readFloats(data []byte) []float64 {
+	c := make([]float64, len(data)/8)
+	for n := range c {
+		c[n] = math.Float64frombits(binary.LittleEndian.Uint64(data[n*8:]))
+	}
+
+	return c
+}
@@ -0,0 +1,319 @@
+package go_quaternions
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+)
+
+func TestQuaternion_MarshalJSON(t *testing.T) {
+	q := NewQuaternionByCoords(1, 2, 3, 4)
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+		back  func(data []byte) (*Quaternion, error)
+	}{
+		{
+			name:  "object",
+			value: q,
+			want:  `{"w":1,"i":2,"j":3,"k":4}`,
+			back: func(data []byte) (*Quaternion, error) {
+				got := &Quaternion{}
+				return got, json.Unmarshal(data, got)
+			},
+		},
+		{
+			name:  "array with scalar first",
+			value: (*QuaternionWXYZ)(q),
+			want:  `[1,2,3,4]`,
+			back: func(data []byte) (*Quaternion, error) {
+				got := &QuaternionWXYZ{}
+				return got.Quaternion(), json.Unmarshal(data, got)
+			},
+		},
+		{
+			name:  "array with scalar last",
+			value: (*QuaternionXYZW)(q),
+			want:  `[2,3,4,1]`,
+			back: func(data []byte) (*Quaternion, error) {
+				got := &QuaternionXYZW{}
+				return got.Quaternion(), json.Unmarshal(data, got)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Wrong JSON for %v. Expected %v, got %v", q, tt.want, string(data))
+			}
+
+			got, err := tt.back(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *q {
+				t.Errorf("Wrong result of JSON round trip. Expected %v, got %v", q, got)
+			}
+		})
+	}
+
+	// Objects are accepted by the array types, and plain quaternions read
+	// arrays with the scalar first.
+	got := &QuaternionXYZW{}
+	if err := json.Unmarshal([]byte(`{"w":1,"i":2,"j":3,"k":4}`), got); err != nil || *got.Quaternion() != *q {
+		t.Errorf("Wrong result of object for array type. Expected %v, got %v (%v)", q, got.Quaternion(), err)
+	}
+	plain := &Quaternion{}
+	if err := json.Unmarshal([]byte(`[1,2,3,4]`), plain); err != nil || *plain != *q {
+		t.Errorf("Wrong result of array for Quaternion. Expected %v, got %v (%v)", q, plain, err)
+	}
+}
+
+func TestQuaternion_MarshalJSON_ShouldSelectShapePerField(t *testing.T) {
+	type config struct {
+		Camera QuaternionXYZW `json:"camera"`
+		IMU    QuaternionWXYZ `json:"imu"`
+		Base   Quaternion     `json:"base"`
+	}
+	c := config{Camera: QuaternionXYZW{W: 1, K: 2}, IMU: QuaternionWXYZ{W: 3, I: 4}, Base: Quaternion{W: 5, J: 6}}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"camera":[0,0,2,1],"imu":[3,4,0,0],"base":{"w":5,"i":0,"j":6,"k":0}}`; string(data) != want {
+		t.Errorf("Wrong JSON for config. Expected %s, got %s", want, data)
+	}
+
+	var got config
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != c {
+		t.Errorf("Wrong result of JSON round trip. Expected %+v, got %+v", c, got)
+	}
+}
+
+func TestMarshal_ShouldNotDependOnAddressability(t *testing.T) {
+	type config struct {
+		Q  Quaternion
+		BQ BQuaternion
+		V  Vec3
+	}
+	c := config{
+		Q:  Quaternion{W: 1, K: -2},
+		BQ: BQuaternion{P: NewQuaternionByCoords(1, 0, 0, 0), Q: NewQuaternionByCoords(0, 1, 2, 3)},
+		V:  Vec3{X: 1, Z: 0.5},
+	}
+
+	byValue, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPointer, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(byValue) != string(byPointer) {
+		t.Errorf("Wrong JSON for value. Expected %s, got %s", byPointer, byValue)
+	}
+
+	var got config
+	if err := json.Unmarshal(byValue, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Q != c.Q || *got.BQ.P != *c.BQ.P || *got.BQ.Q != *c.BQ.Q || got.V != c.V {
+		t.Errorf("Wrong result of JSON round trip for %s. Expected %+v, got %+v", byValue, c, got)
+	}
+
+	// Map keys are never addressable and use MarshalText.
+	text, err := json.Marshal(map[Quaternion]int{c.Q: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"` + c.Q.String() + `":1}`; string(text) != want {
+		t.Errorf("Wrong JSON for map key. Expected %s, got %s", want, text)
+	}
+}
+
+func TestQuaternion_UnmarshalJSON_ShouldFailForWrongLength(t *testing.T) {
+	q := &Quaternion{}
+	if err := json.Unmarshal([]byte(`[1, 2, 3]`), q); err == nil {
+		t.Errorf("Expected error for short array, got %v", q)
+	}
+}
+
+func TestBQuaternion_MarshalJSON(t *testing.T) {
+	bq := NewBQuaternion(
+		NewQuaternionByCoords(rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64()),
+		NewQuaternionByCoords(rand.Float64(), rand.Float64(), rand.Float64(), rand.Float64()),
+	)
+	pose := struct {
+		Name   string           `json:"name"`
+		Pose   *BQuaternion     `json:"pose"`
+		Scalar *BQuaternionWXYZ `json:"scalar"`
+		ROS    *BQuaternionXYZW `json:"ros"`
+	}{Name: "base", Pose: bq, Scalar: (*BQuaternionWXYZ)(bq), ROS: (*BQuaternionXYZW)(bq)}
+
+	data, err := json.Marshal(pose)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pose.Pose, pose.Scalar, pose.ROS = &BQuaternion{}, &BQuaternionWXYZ{}, &BQuaternionXYZW{}
+	if err := json.Unmarshal(data, &pose); err != nil {
+		t.Fatal(err)
+	}
+	for _, got := range []*BQuaternion{pose.Pose, pose.Scalar.BQuaternion(), pose.ROS.BQuaternion()} {
+		if *got.P != *bq.P || *got.Q != *bq.Q {
+			t.Errorf("Wrong result of JSON round trip for %s. Expected %v, got %v", data, bq, got)
+		}
+	}
+
+	ros, _ := json.Marshal(BQuaternionXYZW{P: NewQuaternionByCoords(1, 2, 3, 4), Q: NewQuaternionByCoords(5, 6, 7, 8)})
+	if want := `[[2,3,4,1],[6,7,8,5]]`; string(ros) != want {
+		t.Errorf("Wrong JSON for scalar last BQuaternion. Expected %s, got %s", want, ros)
+	}
+	if err := json.Unmarshal([]byte(`[[1,2,3,4]]`), &BQuaternion{}); err == nil {
+		t.Errorf("Expected error for array with one part")
+	}
+}
+
+func TestBQuaternion_Marshal_ShouldRoundTripZeroValue(t *testing.T) {
+	zero := &Quaternion{}
+	check := func(name string, got *BQuaternion) {
+		t.Helper()
+		if got.P == nil || got.Q == nil || *got.P != *zero || *got.Q != *zero {
+			t.Errorf("Wrong result of %s round trip for zero value. Expected zero parts, got %+v", name, got)
+		}
+	}
+
+	for _, value := range []interface{}{BQuaternion{}, BQuaternionWXYZ{}, BQuaternionXYZW{}} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := &BQuaternion{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatalf("Wrong result of decoding %s: %v", data, err)
+		}
+		check("JSON", got)
+	}
+
+	text, err := BQuaternion{}.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &BQuaternion{}
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	check("text", got)
+
+	data, err := BQuaternion{}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = &BQuaternion{}
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	check("binary", got)
+}
+
+func TestBQuaternion_UnmarshalJSON_ShouldFailForMissingPart(t *testing.T) {
+	for _, data := range []string{`{"p":null,"q":{"w":1}}`, `{"p":{"w":1}}`, `[[1,0,0,0],null]`} {
+		if err := json.Unmarshal([]byte(data), &BQuaternion{}); err != MissingJSONPartError {
+			t.Errorf("Wrong error for %s. Expected %v, got %v", data, MissingJSONPartError, err)
+		}
+	}
+}
+
+func TestVec3_MarshalJSON(t *testing.T) {
+	v := Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()}
+
+	for _, value := range []interface{}{v, Vec3Array(v)} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := &Vec3{}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if *got != v {
+			t.Errorf("Wrong result of JSON round trip for %s. Expected %v, got %v", data, v, got)
+		}
+	}
+
+	if data, _ := json.Marshal(Vec3Array{X: 1, Y: 2, Z: 3}); string(data) != `[1,2,3]` {
+		t.Errorf("Wrong JSON for Vec3Array. Expected [1,2,3], got %s", data)
+	}
+}
+
+func TestMarshalText_ShouldRoundTrip(t *testing.T) {
+	q := NewQuaternionByCoords(rand.Float64(), -rand.Float64(), 1e-20, -3e30)
+	bq := NewBQuaternion(q, NewQuaternionByCoords(0, -0.5, rand.Float64(), 2))
+	v := &Vec3{X: -rand.Float64(), Y: 0, Z: 1e10}
+
+	text, err := q.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotQ := &Quaternion{}
+	if err := gotQ.UnmarshalText(text); err != nil || *gotQ != *q {
+		t.Errorf("Wrong result of text round trip for %s. Expected %v, got %v (%v)", text, q, gotQ, err)
+	}
+
+	text, err = bq.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotBQ := &BQuaternion{}
+	if err := gotBQ.UnmarshalText(text); err != nil || *gotBQ.P != *bq.P || *gotBQ.Q != *bq.Q {
+		t.Errorf("Wrong result of text round trip for %s. Expected %v, got %v (%v)", text, bq, gotBQ, err)
+	}
+
+	text, err = v.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotV := &Vec3{}
+	if err := gotV.UnmarshalText(text); err != nil || *gotV != *v {
+		t.Errorf("Wrong result of text round trip for %s. Expected %v, got %v (%v)", text, v, gotV, err)
+	}
+}
+
+func TestMarshalBinary_ShouldRoundTrip(t *testing.T) {
+	q := NewQuaternionByCoords(rand.Float64(), -rand.Float64(), rand.Float64(), -rand.Float64())
+	bq := NewBQuaternion(q, NewQuaternionByCoords(rand.Float64(), 1, 2, 3))
+	v := &Vec3{X: rand.Float64(), Y: -1, Z: 0.5}
+
+	data, _ := q.MarshalBinary()
+	gotQ := &Quaternion{}
+	if err := gotQ.UnmarshalBinary(data); err != nil || *gotQ != *q || len(data) != 32 {
+		t.Errorf("Wrong result of binary round trip. Expected %v, got %v (%v)", q, gotQ, err)
+	}
+
+	data, _ = bq.MarshalBinary()
+	gotBQ := &BQuaternion{}
+	if err := gotBQ.UnmarshalBinary(data); err != nil || *gotBQ.P != *bq.P || *gotBQ.Q != *bq.Q || len(data) != 64 {
+		t.Errorf("Wrong result of binary round trip. Expected %v, got %v (%v)", bq, gotBQ, err)
+	}
+
+	data, _ = v.MarshalBinary()
+	gotV := &Vec3{}
+	if err := gotV.UnmarshalBinary(data); err != nil || *gotV != *v || len(data) != 24 {
+		t.Errorf("Wrong result of binary round trip. Expected %v, got %v (%v)", v, gotV, err)
+	}
+
+	if err := gotQ.UnmarshalBinary(data); err == nil {
+		t.Errorf("Expected error for %v bytes", len(data))
+	}
+}
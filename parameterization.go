@@ -0,0 +1,164 @@
+package go_quaternions
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"math/cmplx"
+)
+
+var SingularParameterizationError = errors.WithStack(errors.New("Rotation is singular in this parameterization"))
+
+// SU2 is the Cayley-Klein form of a quaternion, the complex 2x2 matrix
+//
+//	[ α   β ]    α = w - zi
+//	[ -β* α*]    β = -y - xi
+//
+// which maps i, j, k to -iσx, -iσy, -iσz, so that products of quaternions
+// become matrix products. A vector v rotates as U (v·σ) U†.
+type SU2 [2][2]complex128
+
+// Gibbs returns the Gibbs (classical Rodrigues) vector tan(θ/2)·n. It is
+// singular for half turns.
+func (q *Quaternion) Gibbs() (*Vec3, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if normQ.W == 0 {
+		return nil, errors.Wrap(SingularParameterizationError, "Gibbs vector of a half turn")
+	}
+
+	return &Vec3{X: normQ.I / normQ.W, Y: normQ.J / normQ.W, Z: normQ.K / normQ.W}, nil
+}
+
+func NewQuaternionFromGibbs(g *Vec3) *Quaternion {
+	s := 1 / math.Sqrt(1+g.X*g.X+g.Y*g.Y+g.Z*g.Z)
+
+	return NewQuaternionByCoords(s, s*g.X, s*g.Y, s*g.Z)
+}
+
+// MRP returns the modified Rodrigues parameters tan(θ/4)·n of the shorter
+// rotation, so that the result has length at most 1.
+func (q *Quaternion) MRP() (*Vec3, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if normQ.W < 0 {
+		normQ = normQ.MulByNumber(-1)
+	}
+
+	s := 1 / (1 + normQ.W)
+
+	return &Vec3{X: s * normQ.I, Y: s * normQ.J, Z: s * normQ.K}, nil
+}
+
+func NewQuaternionFromMRP(sigma *Vec3) *Quaternion {
+	n := sigma.X*sigma.X + sigma.Y*sigma.Y + sigma.Z*sigma.Z
+	s := 2 / (1 + n)
+
+	return NewQuaternionByCoords((1-n)/(1+n), s*sigma.X, s*sigma.Y, s*sigma.Z)
+}
+
+// ShadowMRP returns the shadow set -σ/|σ|² of sigma, which describes the
+// same rotation the other way round. The shadow of zero is singular.
+func ShadowMRP(sigma *Vec3) (*Vec3, error) {
+	n := sigma.X*sigma.X + sigma.Y*sigma.Y + sigma.Z*sigma.Z
+	if n == 0 {
+		return nil, errors.Wrap(SingularParameterizationError, "shadow MRP of the identity")
+	}
+
+	return &Vec3{X: -sigma.X / n, Y: -sigma.Y / n, Z: -sigma.Z / n}, nil
+}
+
+// SwitchMRP returns sigma or its shadow, whichever has length at most 1.
+// Integrators of σ' call it after every step to stay away from the
+// singularity at a full turn.
+func SwitchMRP(sigma *Vec3) *Vec3 {
+	if sigma.X*sigma.X+sigma.Y*sigma.Y+sigma.Z*sigma.Z <= 1 {
+		return &Vec3{X: sigma.X, Y: sigma.Y, Z: sigma.Z}
+	}
+	shadow, _ := ShadowMRP(sigma)
+
+	return shadow
+}
+
+// RotationVector returns θ·n of the shorter rotation, with θ in [0, π].
+func (q *Quaternion) RotationVector() (*Vec3, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if normQ.W < 0 {
+		normQ = normQ.MulByNumber(-1)
+	}
+
+	sin := math.Sqrt(normQ.I*normQ.I + normQ.J*normQ.J + normQ.K*normQ.K)
+	if sin == 0 {
+		return &Vec3{}, nil
+	}
+	s := 2 * math.Atan2(sin, normQ.W) / sin
+
+	return &Vec3{X: s * normQ.I, Y: s * normQ.J, Z: s * normQ.K}, nil
+}
+
+func NewQuaternionFromRotationVector(v *Vec3) *Quaternion {
+	angle := v.Length()
+	if angle == 0 {
+		return NewQuaternionByCoords(1, 0, 0, 0)
+	}
+	s := math.Sin(angle/2) / angle
+
+	return NewQuaternionByCoords(math.Cos(angle/2), s*v.X, s*v.Y, s*v.Z)
+}
+
+// SU2 returns the Cayley-Klein matrix of q. It is unitary with determinant
+// one when q is a unit quaternion.
+func (q *Quaternion) SU2() SU2 {
+	alpha := complex(q.W, -q.K)
+	beta := complex(-q.J, -q.I)
+
+	return SU2{
+		{alpha, beta},
+		{-cmplx.Conj(beta), cmplx.Conj(alpha)},
+	}
+}
+
+// CayleyKlein returns the parameters α and β of q.
+func (q *Quaternion) CayleyKlein() (complex128, complex128) {
+	u := q.SU2()
+
+	return u[0][0], u[0][1]
+}
+
+// NewQuaternionFromSU2 reads a quaternion back from its Cayley-Klein
+// matrix. Matrices not of that form are projected onto it.
+func NewQuaternionFromSU2(u SU2) *Quaternion {
+	alpha := (u[0][0] + cmplx.Conj(u[1][1])) / 2
+	beta := (u[0][1] - cmplx.Conj(u[1][0])) / 2
+
+	return NewQuaternionByCoords(real(alpha), -imag(beta), -real(beta), -imag(alpha))
+}
+
+func (u SU2) Mul(arg SU2) SU2 {
+	var res SU2
+	for r := range res {
+		for c := range res[r] {
+			res[r][c] = u[r][0]*arg[0][c] + u[r][1]*arg[1][c]
+		}
+	}
+
+	return res
+}
+
+// ConjugateTranspose returns U†, the inverse of a unitary U.
+func (u SU2) ConjugateTranspose() SU2 {
+	return SU2{
+		{cmplx.Conj(u[0][0]), cmplx.Conj(u[1][0])},
+		{cmplx.Conj(u[0][1]), cmplx.Conj(u[1][1])},
+	}
+}
+
+func (u SU2) Det() complex128 {
+	return u[0][0]*u[1][1] - u[0][1]*u[1][0]
+}
@@ -0,0 +1,151 @@
+package go_quaternions
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func randomRotation() *Quaternion {
+	q, _ := NewQuaternionByCoords(rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5).Normalize()
+	return q
+}
+
+func TestParameterizations_QuarterTurn(t *testing.T) {
+	q, _ := NewQuaternionByCoords(0, 0, 0, 1).ToRotateQuaternion(math.Pi / 2)
+
+	tests := []struct {
+		name string
+		got  func() (*Vec3, error)
+		want *Vec3
+	}{
+		{name: "gibbs", got: q.Gibbs, want: &Vec3{Z: 1}},
+		{name: "mrp", got: q.MRP, want: &Vec3{Z: math.Tan(math.Pi / 8)}},
+		{name: "rotation vector", got: q.RotationVector, want: &Vec3{Z: math.Pi / 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.got()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equals(tt.want, EqualsEpsilon) {
+				t.Errorf("Wrong result of %s for %v. Expected %v, got %v", tt.name, q, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParameterizations_RoundTrip(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		q := randomRotation()
+
+		g, err := q.Gibbs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := NewQuaternionFromGibbs(g); !got.EqualsRotation(q, 1e-12) {
+			t.Errorf("Wrong result of Gibbs round trip. Expected %v, got %v", q, got)
+		}
+
+		sigma, err := q.MRP()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sigma.Length() > 1+EqualsEpsilon {
+			t.Errorf("Expected MRP of length at most 1, got %v", sigma)
+		}
+		if got := NewQuaternionFromMRP(sigma); !got.EqualsRotation(q, 1e-12) {
+			t.Errorf("Wrong result of MRP round trip. Expected %v, got %v", q, got)
+		}
+
+		v, err := q.RotationVector()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Length() > math.Pi+EqualsEpsilon {
+			t.Errorf("Expected rotation angle at most π, got %v", v.Length())
+		}
+		if got := NewQuaternionFromRotationVector(v); !got.EqualsRotation(q, 1e-12) {
+			t.Errorf("Wrong result of rotation vector round trip. Expected %v, got %v", q, got)
+		}
+
+		if got := NewQuaternionFromSU2(q.SU2()); !got.Equals(q, EqualsEpsilon) {
+			t.Errorf("Wrong result of SU2 round trip. Expected %v, got %v", q, got)
+		}
+	}
+}
+
+func TestShadowMRP(t *testing.T) {
+	q := randomRotation()
+	sigma, _ := q.MRP()
+
+	shadow, err := ShadowMRP(sigma)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shadow.Length() < 1 {
+		t.Errorf("Expected shadow MRP of length at least 1, got %v", shadow)
+	}
+	if got := NewQuaternionFromMRP(shadow); !got.EqualsRotation(q, 1e-12) {
+		t.Errorf("Wrong rotation of shadow MRP. Expected %v, got %v", q, got)
+	}
+	if got := SwitchMRP(shadow); !got.Equals(sigma, 1e-12) {
+		t.Errorf("Wrong result of SwitchMRP. Expected %v, got %v", sigma, got)
+	}
+	if got := SwitchMRP(sigma); *got != *sigma {
+		t.Errorf("Expected SwitchMRP to keep %v, got %v", sigma, got)
+	}
+
+	if _, err := ShadowMRP(&Vec3{}); !errors.Is(err, SingularParameterizationError) {
+		t.Errorf("Expected SingularParameterizationError, got %v", err)
+	}
+}
+
+func TestQuaternion_Gibbs_ShouldFailForHalfTurn(t *testing.T) {
+	if _, err := NewQuaternionByCoords(0, 1, 0, 0).Gibbs(); !errors.Is(err, SingularParameterizationError) {
+		t.Errorf("Expected SingularParameterizationError, got %v", err)
+	}
+	if _, err := NewQuaternionByCoords(0, 0, 0, 0).MRP(); err == nil {
+		t.Errorf("Expected error for zero quaternion")
+	}
+}
+
+func TestQuaternion_SU2(t *testing.T) {
+	p, q := randomRotation(), randomRotation()
+
+	got, want := p.SU2().Mul(q.SU2()), p.MulByGrassmann(q).SU2()
+	for r := range want {
+		for c := range want[r] {
+			if cmplx.Abs(got[r][c]-want[r][c]) > 1e-15 {
+				t.Fatalf("Wrong result of SU2 product. Expected %v, got %v", want, got)
+			}
+		}
+	}
+	if d := p.SU2().Det(); cmplx.Abs(d-1) > 1e-15 {
+		t.Errorf("Wrong determinant of SU2. Expected 1, got %v", d)
+	}
+
+	// Rotating v by U (v·σ) U† must agree with RotateRad.
+	axis, angle, _ := p.ToAxisAngle()
+	v := &Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()}
+	rotated, _ := v.RotateRad(axis, angle)
+
+	u := p.SU2()
+	sigma := SU2{
+		{complex(v.Z, 0), complex(v.X, -v.Y)},
+		{complex(v.X, v.Y), complex(-v.Z, 0)},
+	}
+	r := u.Mul(sigma).Mul(u.ConjugateTranspose())
+	gotV := &Vec3{X: real(r[1][0]), Y: imag(r[1][0]), Z: real(r[0][0])}
+	if !gotV.Equals(rotated, 1e-12) {
+		t.Errorf("Wrong rotation by SU2. Expected %v, got %v", rotated, gotV)
+	}
+
+	alpha, beta := p.CayleyKlein()
+	if alpha != u[0][0] || beta != u[0][1] {
+		t.Errorf("Wrong Cayley-Klein parameters. Expected %v and %v, got %v and %v", u[0][0], u[0][1], alpha, beta)
+	}
+}
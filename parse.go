@@ -0,0 +1,322 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports malformed quaternion literal. Offset is the byte
+// position in Input where parsing stopped.
+type ParseError struct {
+	Input  string
+	Offset int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse quaternion %q: offset %d: %s", e.Input, e.Offset, e.Msg)
+}
+
+// ParseQuaternion reads a quaternion from one of the following forms:
+//
+//	(1)+(2)i+(3)j+(4)k        as printed by Quaternion.String
+//	1+2i-3j+0.5k              terms in any order, missing terms are zero
+//	[1, 2, 3, 4]              components in W, I, J, K order
+//	axisangle(0, 0, 1, 90deg) axis and angle in rad (default), deg or °
+func ParseQuaternion(s string) (*Quaternion, error) {
+	p := &quaternionParser{input: s}
+
+	p.skipSpaces()
+	var (
+		q   *Quaternion
+		err error
+	)
+	switch {
+	case p.pos == len(s):
+		return nil, p.errorf("empty input")
+	case s[p.pos] == '[':
+		q, err = p.parseList()
+	case strings.HasPrefix(s[p.pos:], "axisangle"):
+		q, err = p.parseAxisAngle()
+	default:
+		q, err = p.parseSum()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos != len(s) {
+		return nil, p.errorf("unexpected %q", s[p.pos:])
+	}
+
+	return q, nil
+}
+
+type quaternionParser struct {
+	input string
+	pos   int
+}
+
+func (p *quaternionParser) parseList() (*Quaternion, error) {
+	p.pos++
+
+	c, err := p.parseNumbers(4)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(']'); err != nil {
+		return nil, err
+	}
+
+	return NewQuaternionByCoords(c[0], c[1], c[2], c[3]), nil
+}
+
+func (p *quaternionParser) parseAxisAngle() (*Quaternion, error) {
+	p.pos += len("axisangle")
+	p.skipSpaces()
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	axisPos := p.pos
+	c, err := p.parseNumbers(4)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	angle := c[3]
+	switch rest := p.input[p.pos:]; {
+	case strings.HasPrefix(rest, "deg"):
+		angle = angle * math.Pi / 180
+		p.pos += len("deg")
+	case strings.HasPrefix(rest, "°"):
+		angle = angle * math.Pi / 180
+		p.pos += len("°")
+	case strings.HasPrefix(rest, "rad"):
+		p.pos += len("rad")
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+
+	q, err := NewQuaternionByCoords(0, c[0], c[1], c[2]).ToRotateQuaternion(angle)
+	if err != nil {
+		return nil, &ParseError{Input: p.input, Offset: axisPos, Msg: "zero rotation axis"}
+	}
+
+	return q, nil
+}
+
+// parseNumbers reads n comma separated signed numbers.
+func (p *quaternionParser) parseNumbers(n int) ([]float64, error) {
+	c := make([]float64, n)
+	for m := range c {
+		if m > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+		}
+
+		p.skipSpaces()
+		v, err := p.parseSigned()
+		if err != nil {
+			return nil, err
+		}
+		c[m] = v
+	}
+
+	return c, nil
+}
+
+func (p *quaternionParser) parseSum() (*Quaternion, error) {
+	var (
+		c    [4]float64
+		seen [4]bool
+	)
+
+	for first := true; ; first = false {
+		p.skipSpaces()
+		if p.pos == len(p.input) {
+			if first {
+				return nil, p.errorf("empty input")
+			}
+			break
+		}
+
+		sign := 1.0
+		switch p.input[p.pos] {
+		case '+':
+			p.pos++
+		case '-':
+			sign = -1
+			p.pos++
+		default:
+			if !first {
+				return nil, p.errorf("expected '+' or '-', got %q", p.input[p.pos])
+			}
+		}
+		p.skipSpaces()
+
+		termPos := p.pos
+		coefficient, explicit, err := p.parseCoefficient()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if explicit && p.pos < len(p.input) && p.input[p.pos] == '*' {
+			p.pos++
+			p.skipSpaces()
+		}
+
+		unit := 0
+		if p.pos < len(p.input) {
+			unit = strings.IndexByte("ijk", p.input[p.pos]) + 1
+		}
+		switch {
+		case unit > 0:
+			p.pos++
+		case !explicit:
+			return nil, p.errorf("expected number or unit")
+		case p.pos < len(p.input) && isLetter(p.input[p.pos]):
+			return nil, p.errorf("unknown unit %q", p.input[p.pos])
+		}
+
+		if seen[unit] {
+			return nil, &ParseError{Input: p.input, Offset: termPos, Msg: fmt.Sprintf("duplicate %s", [4]string{"real part", "i term", "j term", "k term"}[unit])}
+		}
+		seen[unit] = true
+		c[unit] = sign * coefficient
+	}
+
+	return NewQuaternionByCoords(c[0], c[1], c[2], c[3]), nil
+}
+
+// parseCoefficient reads a plain or parenthesised number. A missing
+// coefficient (as in "-k") is reported as 1 with explicit set to false.
+func (p *quaternionParser) parseCoefficient() (float64, bool, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		p.skipSpaces()
+		v, err := p.parseSigned()
+		if err != nil {
+			return 0, false, err
+		}
+		if err := p.expect(')'); err != nil {
+			return 0, false, err
+		}
+
+		return v, true, nil
+	}
+
+	if p.numberLength() == 0 {
+		return 1, false, nil
+	}
+
+	v, err := p.parseNumber()
+	return v, true, err
+}
+
+func (p *quaternionParser) parseSigned() (float64, error) {
+	sign := 1.0
+	if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+		if p.input[p.pos] == '-' {
+			sign = -1
+		}
+		p.pos++
+	}
+
+	v, err := p.parseNumber()
+	return sign * v, err
+}
+
+func (p *quaternionParser) parseNumber() (float64, error) {
+	n := p.numberLength()
+	if n == 0 {
+		if p.pos == len(p.input) {
+			return 0, p.errorf("expected number, got end of input")
+		}
+		return 0, p.errorf("expected number, got %q", p.input[p.pos])
+	}
+
+	v, err := strconv.ParseFloat(p.input[p.pos:p.pos+n], 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", p.input[p.pos:p.pos+n])
+	}
+	p.pos += n
+
+	return v, nil
+}
+
+// numberLength returns the length of the unsigned decimal number, Inf or NaN
+// at the current position, or zero if there is none.
+func (p *quaternionParser) numberLength() int {
+	rest := p.input[p.pos:]
+	for _, word := range []string{"infinity", "inf", "nan"} {
+		if len(rest) >= len(word) && strings.EqualFold(rest[:len(word)], word) {
+			return len(word)
+		}
+	}
+
+	n, digits := 0, 0
+	for n < len(rest) && (isDigit(rest[n]) || rest[n] == '.') {
+		if rest[n] != '.' {
+			digits++
+		}
+		n++
+	}
+	if digits == 0 {
+		return 0
+	}
+
+	if n < len(rest) && (rest[n] == 'e' || rest[n] == 'E') {
+		m := n + 1
+		if m < len(rest) && (rest[m] == '+' || rest[m] == '-') {
+			m++
+		}
+		if m < len(rest) && isDigit(rest[m]) {
+			for m < len(rest) && isDigit(rest[m]) {
+				m++
+			}
+			n = m
+		}
+	}
+
+	return n
+}
+
+func (p *quaternionParser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return p.errorf("expected %q, got end of input", c)
+	}
+	if p.input[p.pos] != c {
+		return p.errorf("expected %q, got %q", c, p.input[p.pos])
+	}
+	p.pos++
+
+	return nil
+}
+
+func (p *quaternionParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *quaternionParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Input: p.input, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,133 @@
+package go_quaternions
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestParseQuaternion(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  *Quaternion
+		eps   float64
+	}{
+		{
+			name:  "string format",
+			input: "(1)+(2)i+(3)j+(4)k",
+			want:  NewQuaternionByCoords(1, 2, 3, 4),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "string format with negative and exponent",
+			input: "(-1.5)+(2e-10)i+(-3E+4)j+(0)k",
+			want:  NewQuaternionByCoords(-1.5, 2e-10, -3e4, 0),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "natural form",
+			input: "1+2i-3j+0.5k",
+			want:  NewQuaternionByCoords(1, 2, -3, 0.5),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "natural form with spaces, implicit coefficients and any order",
+			input: "  -k + 2 * i - 0.25 + j ",
+			want:  NewQuaternionByCoords(-0.25, 2, 1, -1),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "pure real",
+			input: "-7",
+			want:  NewQuaternionByCoords(-7, 0, 0, 0),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "list",
+			input: "[1, -2, 3.5, .5]",
+			want:  NewQuaternionByCoords(1, -2, 3.5, 0.5),
+			eps:   EqualsEpsilon,
+		},
+		{
+			name:  "axis angle in degrees",
+			input: "axisangle(0, 0, 2, 90deg)",
+			want:  NewQuaternionByCoords(math.Sqrt2/2, 0, 0, math.Sqrt2/2),
+			eps:   1e-15,
+		},
+		{
+			name:  "axis angle in radians",
+			input: "axisangle(1, 0, 0, 3.141592653589793)",
+			want:  NewQuaternionByCoords(0, 1, 0, 0),
+			eps:   1e-15,
+		},
+		{
+			name:  "axis angle with degree sign",
+			input: "axisangle(0,1,0,-60°)",
+			want:  NewQuaternionByCoords(math.Sqrt(3)/2, 0, -0.5, 0),
+			eps:   1e-15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuaternion(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equals(tt.want, tt.eps) {
+				t.Errorf("Wrong result of parse for %q. Expected %v, got %v", tt.input, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseQuaternion_ShouldRoundTripString(t *testing.T) {
+	for n := 0; n < 20; n++ {
+		q := NewQuaternionByCoords(rand.NormFloat64(), rand.NormFloat64()*1e8, -rand.Float64()*1e-8, rand.NormFloat64())
+
+		got, err := ParseQuaternion(q.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != *q {
+			t.Errorf("Wrong result of round trip for %v, got %v", q, got)
+		}
+	}
+
+	got, err := ParseQuaternion(NewQuaternionByCoords(math.Inf(1), math.Inf(-1), 0, 0).String())
+	if err != nil || !math.IsInf(got.W, 1) || !math.IsInf(got.I, -1) {
+		t.Errorf("Wrong result of round trip for infinities, got %v (%v)", got, err)
+	}
+}
+
+func TestParseQuaternion_ShouldFailWithOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		offset int
+	}{
+		{name: "empty", input: "  ", offset: 2},
+		{name: "missing operator", input: "1+2i 3j", offset: 5},
+		{name: "duplicate term", input: "1+2i-3i", offset: 5},
+		{name: "unknown unit", input: "1+2x", offset: 3},
+		{name: "unclosed parenthesis", input: "(1)+(2i", offset: 6},
+		{name: "short list", input: "[1, 2, 3]", offset: 8},
+		{name: "trailing garbage", input: "[1, 2, 3, 4] x", offset: 13},
+		{name: "zero axis", input: "axisangle(0, 0, 0, 1)", offset: 10},
+		{name: "bad number", input: "1.2.3+i", offset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuaternion(tt.input)
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Expected ParseError for %q, got %v", tt.input, err)
+			}
+			if perr.Offset != tt.offset {
+				t.Errorf("Wrong offset for %q. Expected %v, got %v (%v)", tt.input, tt.offset, perr.Offset, err)
+			}
+		})
+	}
+}
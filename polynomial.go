@@ -0,0 +1,376 @@
+package go_quaternions
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+const (
+	aberthIterations = 500
+	newtonIterations = 20
+	// realRootTolerance is the largest imaginary part, relative to the
+	// modulus, of a companion root that counts as real.
+	realRootTolerance = 1e-8
+	epsilon           = 0x1p-52
+	// sphericalTolerance bounds the remainder, relative to the size of the
+	// polynomial on the sphere, below which a sphere counts as roots.
+	sphericalTolerance = 1e-9
+)
+
+var ZeroPolynomialError = errors.WithStack(errors.New("Polynomial is zero"))
+
+// Polynomial is a unilateral polynomial a₀ + a₁x + ... + aₙxⁿ with the
+// coefficients on the left. The variable commutes with the coefficients in
+// sums and products, but not when a quaternion is substituted, so
+// evaluation of a product is not the product of evaluations.
+type Polynomial struct {
+	// Coefficients[n] multiplies xⁿ.
+	Coefficients []*Quaternion
+}
+
+// Root is a zero of a Polynomial. An isolated root is Value itself. A
+// spherical root is every quaternion with the real part and the vector
+// length of Value, which is then W + ri for the radius r.
+type Root struct {
+	Value     *Quaternion
+	Spherical bool
+}
+
+// NewPolynomial copies the coefficients, lowest degree first.
+func NewPolynomial(coefficients ...*Quaternion) *Polynomial {
+	p := &Polynomial{Coefficients: make([]*Quaternion, len(coefficients))}
+	for n, c := range coefficients {
+		p.Coefficients[n] = NewQuaternionByCoords(c.W, c.I, c.J, c.K)
+	}
+
+	return p
+}
+
+// Degree returns the index of the highest non-zero coefficient, or -1 for
+// the zero polynomial.
+func (p *Polynomial) Degree() int {
+	for n := len(p.Coefficients) - 1; n >= 0; n-- {
+		if p.Coefficients[n].Norm() != 0 {
+			return n
+		}
+	}
+
+	return -1
+}
+
+// Evaluate returns Σ aₙxⁿ by Horner's scheme, multiplying by x on the right.
+func (p *Polynomial) Evaluate(x *Quaternion) *Quaternion {
+	result := NewQuaternionByCoords(0, 0, 0, 0)
+	for n := len(p.Coefficients) - 1; n >= 0; n-- {
+		result = result.MulByGrassmann(x).Add(p.Coefficients[n])
+	}
+
+	return result
+}
+
+func (p *Polynomial) Add(arg *Polynomial) *Polynomial {
+	size := len(p.Coefficients)
+	if len(arg.Coefficients) > size {
+		size = len(arg.Coefficients)
+	}
+
+	sum := &Polynomial{Coefficients: make([]*Quaternion, size)}
+	for n := range sum.Coefficients {
+		sum.Coefficients[n] = p.coefficient(n).Add(arg.coefficient(n))
+	}
+
+	return sum
+}
+
+func (p *Polynomial) Sub(arg *Polynomial) *Polynomial {
+	return p.Add(arg.MulByNumber(-1))
+}
+
+// Mul returns the product with x central, Σ aᵢbⱼxⁱ⁺ʲ.
+func (p *Polynomial) Mul(arg *Polynomial) *Polynomial {
+	if len(p.Coefficients) == 0 || len(arg.Coefficients) == 0 {
+		return &Polynomial{}
+	}
+
+	product := &Polynomial{Coefficients: make([]*Quaternion, len(p.Coefficients)+len(arg.Coefficients)-1)}
+	for n := range product.Coefficients {
+		product.Coefficients[n] = NewQuaternionByCoords(0, 0, 0, 0)
+	}
+	for i, a := range p.Coefficients {
+		for j, b := range arg.Coefficients {
+			product.Coefficients[i+j] = product.Coefficients[i+j].Add(a.MulByGrassmann(b))
+		}
+	}
+
+	return product
+}
+
+func (p *Polynomial) MulByNumber(n float64) *Polynomial {
+	result := &Polynomial{Coefficients: make([]*Quaternion, len(p.Coefficients))}
+	for k, c := range p.Coefficients {
+		result.Coefficients[k] = c.MulByNumber(n)
+	}
+
+	return result
+}
+
+// Conjugate conjugates every coefficient.
+func (p *Polynomial) Conjugate() *Polynomial {
+	result := &Polynomial{Coefficients: make([]*Quaternion, len(p.Coefficients))}
+	for k, c := range p.Coefficients {
+		result.Coefficients[k] = c.Conjugate()
+	}
+
+	return result
+}
+
+// Companion returns the real coefficients of p·p̄, lowest degree first.
+// Every root of p lies on a sphere W + ru whose W ± ri are complex roots
+// of the companion polynomial.
+func (p *Polynomial) Companion() []float64 {
+	product := p.Mul(p.Conjugate())
+
+	companion := make([]float64, len(product.Coefficients))
+	for n, c := range product.Coefficients {
+		companion[n] = c.W
+	}
+
+	return companion
+}
+
+// Roots finds the roots of p after Niven and Serôdio: the complex roots of
+// the companion polynomial, found by the Aberth method, give the spheres
+// that hold roots, and the remainder of p divided by the real quadratic of
+// a sphere, Ax + B, tells whether the whole sphere is a root (A = B = 0) or
+// only -A⁻¹B is. Real roots are isolated. Roots come in ascending order of
+// W and then radius.
+func (p *Polynomial) Roots() ([]Root, error) {
+	degree := p.Degree()
+	if degree < 0 {
+		return nil, ZeroPolynomialError
+	}
+
+	var roots []Root
+	companion := p.Companion()[:2*degree+1]
+	for _, z := range clusterRoots(companion, aberth(companion)) {
+		alpha, beta := real(z), imag(z)
+		if math.Abs(beta) <= realRootTolerance*math.Max(1, cmplx.Abs(z)) {
+			roots = append(roots, Root{Value: NewQuaternionByCoords(alpha, 0, 0, 0)})
+			continue
+		}
+		if beta < 0 {
+			continue
+		}
+
+		a, b := p.divideByQuadratic(-2*alpha, alpha*alpha+beta*beta)
+		scale := 0.0
+		for n := degree; n >= 0; n-- {
+			scale = scale*cmplx.Abs(z) + math.Sqrt(p.Coefficients[n].Norm())
+		}
+		if math.Sqrt(a.Norm())*cmplx.Abs(z)+math.Sqrt(b.Norm()) <= sphericalTolerance*scale {
+			roots = append(roots, Root{Value: NewQuaternionByCoords(alpha, beta, 0, 0), Spherical: true})
+			continue
+		}
+
+		reverse, err := a.Reverse()
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, Root{Value: reverse.MulByGrassmann(b).MulByNumber(-1)})
+	}
+
+	sort.Slice(roots, func(a, b int) bool {
+		if roots[a].Value.W != roots[b].Value.W {
+			return roots[a].Value.W < roots[b].Value.W
+		}
+
+		return roots[a].Value.Sub(NewQuaternionByCoords(roots[a].Value.W, 0, 0, 0)).Norm() <
+			roots[b].Value.Sub(NewQuaternionByCoords(roots[b].Value.W, 0, 0, 0)).Norm()
+	})
+
+	return roots, nil
+}
+
+func (p *Polynomial) coefficient(n int) *Quaternion {
+	if n < len(p.Coefficients) {
+		return p.Coefficients[n]
+	}
+
+	return NewQuaternionByCoords(0, 0, 0, 0)
+}
+
+// divideByQuadratic returns the remainder Ax + B of p divided by the real
+// polynomial x² + sx + t, which vanishes on the sphere of its roots.
+func (p *Polynomial) divideByQuadratic(s, t float64) (*Quaternion, *Quaternion) {
+	r := NewPolynomial(p.Coefficients...).Coefficients
+	for n := len(r) - 1; n >= 2; n-- {
+		r[n-1] = r[n-1].Sub(r[n].MulByNumber(s))
+		r[n-2] = r[n-2].Sub(r[n].MulByNumber(t))
+	}
+
+	zero := NewQuaternionByCoords(0, 0, 0, 0)
+	switch len(r) {
+	case 0:
+		return zero, zero
+	case 1:
+		return zero, r[0]
+	}
+
+	return r[1], r[0]
+}
+
+// aberth returns the complex roots of the real polynomial with the given
+// coefficients, lowest degree first and the last one non-zero, by the
+// Aberth-Ehrlich iteration.
+func aberth(coefficients []float64) []complex128 {
+	degree := len(coefficients) - 1
+	if degree < 1 {
+		return nil
+	}
+
+	// All roots lie within the Cauchy bound.
+	bound := 0.0
+	for _, c := range coefficients[:degree] {
+		bound = math.Max(bound, math.Abs(c/coefficients[degree]))
+	}
+	z := make([]complex128, degree)
+	for k := range z {
+		z[k] = cmplx.Rect(1+bound, 2*math.Pi*float64(k)/float64(degree)+0.4)
+	}
+
+	for iteration := 0; iteration < aberthIterations; iteration++ {
+		converged := true
+		for k := range z {
+			value, derivative := evaluateReal(coefficients, z[k])
+			if value == 0 {
+				continue
+			}
+
+			ratio := value / derivative
+			var repulsion complex128
+			for j := range z {
+				if j != k {
+					repulsion += 1 / (z[k] - z[j])
+				}
+			}
+			step := ratio / (1 - ratio*repulsion)
+			z[k] -= step
+
+			if cmplx.Abs(step) > 1e-15*math.Max(1, cmplx.Abs(z[k])) {
+				converged = false
+			}
+		}
+		if converged {
+			break
+		}
+	}
+
+	return z
+}
+
+func evaluateReal(coefficients []float64, z complex128) (complex128, complex128) {
+	var value, derivative complex128
+	for n := len(coefficients) - 1; n >= 0; n-- {
+		derivative = derivative*z + value
+		value = value*z + complex(coefficients[n], 0)
+	}
+
+	return value, derivative
+}
+
+// clusterRoots merges the roots whose inclusion disks overlap. The disk
+// around zₖ has radius n|p(zₖ)/(aₙ∏(zₖ - zⱼ))|, with the rounding error of
+// p(zₖ) added, and every connected group of m disks holds exactly m roots.
+// The copies of a root of multiplicity m spread by about the m-th root of
+// the rounding error, so their mean is polished by Newton's method on the
+// (m-1)-th derivative, where the root is simple.
+func clusterRoots(coefficients []float64, z []complex128) []complex128 {
+	degree := len(z)
+	radius := make([]float64, degree)
+	for k := range z {
+		value, _ := evaluateReal(coefficients, z[k])
+		rounding := 0.0
+		for n := degree; n >= 0; n-- {
+			rounding = rounding*cmplx.Abs(z[k]) + math.Abs(coefficients[n])
+		}
+
+		product := complex(coefficients[degree], 0)
+		for j := range z {
+			if j != k {
+				product *= z[k] - z[j]
+			}
+		}
+		radius[k] = float64(degree) * (cmplx.Abs(value) + 4*float64(degree)*epsilon*rounding) / cmplx.Abs(product)
+	}
+
+	group := make([]int, degree)
+	for k := range group {
+		group[k] = k
+	}
+	var find func(int) int
+	find = func(k int) int {
+		if group[k] != k {
+			group[k] = find(group[k])
+		}
+
+		return group[k]
+	}
+	for k := range z {
+		for j := k + 1; j < degree; j++ {
+			if cmplx.Abs(z[k]-z[j]) <= radius[k]+radius[j] {
+				group[find(k)] = find(j)
+			}
+		}
+	}
+
+	sums := make(map[int]complex128)
+	counts := make(map[int]int)
+	var order []int
+	for k := range z {
+		g := find(k)
+		if counts[g] == 0 {
+			order = append(order, g)
+		}
+		sums[g] += z[k]
+		counts[g]++
+	}
+
+	clusters := make([]complex128, len(order))
+	for n, g := range order {
+		clusters[n] = newtonPolish(derivative(coefficients, counts[g]-1), sums[g]/complex(float64(counts[g]), 0))
+	}
+
+	return clusters
+}
+
+// derivative returns the coefficients of the order-th derivative.
+func derivative(coefficients []float64, order int) []float64 {
+	d := append([]float64(nil), coefficients...)
+	for ; order > 0 && len(d) > 1; order-- {
+		for n := 1; n < len(d); n++ {
+			d[n-1] = float64(n) * d[n]
+		}
+		d = d[:len(d)-1]
+	}
+
+	return d
+}
+
+func newtonPolish(coefficients []float64, z complex128) complex128 {
+	for iteration := 0; iteration < newtonIterations; iteration++ {
+		value, slope := evaluateReal(coefficients, z)
+		if slope == 0 {
+			break
+		}
+
+		step := value / slope
+		z -= step
+		if cmplx.Abs(step) <= epsilon*math.Max(1, cmplx.Abs(z)) {
+			break
+		}
+	}
+
+	return z
+}
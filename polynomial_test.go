@@ -0,0 +1,207 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomQuaternion() *Quaternion {
+	return NewQuaternionByCoords(rand.Float64()*2-1, rand.Float64()*2-1, rand.Float64()*2-1, rand.Float64()*2-1)
+}
+
+// linearFactors returns (x - q₁)(x - q₂)...(x - qₙ), which has qₙ as a root
+// and a root on the sphere of every qₖ.
+func linearFactors(q ...*Quaternion) *Polynomial {
+	p := NewPolynomial(NewQuaternionByCoords(1, 0, 0, 0))
+	for _, r := range q {
+		p = p.Mul(NewPolynomial(r.MulByNumber(-1), NewQuaternionByCoords(1, 0, 0, 0)))
+	}
+
+	return p
+}
+
+func sphereOf(q *Quaternion) (float64, float64) {
+	return q.W, math.Sqrt(q.I*q.I + q.J*q.J + q.K*q.K)
+}
+
+func TestPolynomial_Arithmetic(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		a := NewPolynomial(randomQuaternion(), randomQuaternion(), randomQuaternion())
+		b := NewPolynomial(randomQuaternion(), randomQuaternion())
+		x := randomQuaternion()
+
+		naive := NewQuaternionByCoords(0, 0, 0, 0)
+		power := NewQuaternionByCoords(1, 0, 0, 0)
+		for _, c := range a.Coefficients {
+			naive = naive.Add(c.MulByGrassmann(power))
+			power = power.MulByGrassmann(x)
+		}
+		if got := a.Evaluate(x); !got.equalsByCoords(naive, 1e-13) {
+			t.Errorf("Wrong result of evaluate for %v at %v. Expected %v, got %v", a.Coefficients, x, naive, got)
+		}
+
+		if got, want := a.Add(b).Evaluate(x), a.Evaluate(x).Add(b.Evaluate(x)); !got.equalsByCoords(want, 1e-13) {
+			t.Errorf("Wrong result of add. Expected %v, got %v", want, got)
+		}
+		if got, want := a.Sub(b).Evaluate(x), a.Evaluate(x).Sub(b.Evaluate(x)); !got.equalsByCoords(want, 1e-13) {
+			t.Errorf("Wrong result of sub. Expected %v, got %v", want, got)
+		}
+
+		// A real argument commutes with the coefficients, so evaluation is
+		// multiplicative there.
+		r := NewQuaternionByCoords(rand.Float64()*2-1, 0, 0, 0)
+		if got, want := a.Mul(b).Evaluate(r), a.Evaluate(r).MulByGrassmann(b.Evaluate(r)); !got.equalsByCoords(want, 1e-13) {
+			t.Errorf("Wrong result of mul at real %v. Expected %v, got %v", r, want, got)
+		}
+		if got := a.Mul(b).Degree(); got != 3 {
+			t.Errorf("Wrong degree of product. Expected 3, got %d", got)
+		}
+
+		// The companion polynomial is |p(r)|² on the real line.
+		if got, want := NewPolynomial(realCoefficients(a.Companion())...).Evaluate(r).W, a.Evaluate(r).Norm(); math.Abs(got-want) > 1e-14 {
+			t.Errorf("Wrong companion polynomial at %v. Expected %v, got %v", r, want, got)
+		}
+	}
+}
+
+func realCoefficients(c []float64) []*Quaternion {
+	q := make([]*Quaternion, len(c))
+	for n, v := range c {
+		q[n] = NewQuaternionByCoords(v, 0, 0, 0)
+	}
+
+	return q
+}
+
+func TestPolynomial_Roots_Isolated(t *testing.T) {
+	for n := 0; n < 100; n++ {
+		q := []*Quaternion{randomQuaternion(), randomQuaternion(), randomQuaternion()}
+		p := linearFactors(q...)
+
+		roots, err := p.Roots()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roots) != len(q) {
+			t.Errorf("Wrong number of roots of %v. Expected %d, got %v", p.Coefficients, len(q), roots)
+			continue
+		}
+
+		for _, r := range roots {
+			if r.Spherical {
+				t.Errorf("Wrong spherical root %v for %v", r.Value, q)
+			}
+			if got := p.Evaluate(r.Value); math.Sqrt(got.Norm()) > 1e-9 {
+				t.Errorf("Wrong root %v for %v. Value is %v", r.Value, q, got)
+			}
+		}
+
+		found := false
+		for _, r := range roots {
+			found = found || r.Value.equalsByCoords(q[len(q)-1], 1e-9)
+		}
+		if !found {
+			t.Errorf("Wrong roots for %v. Expected %v among %v", q, q[len(q)-1], roots)
+		}
+
+		for _, f := range q {
+			w, radius := sphereOf(f)
+			found := false
+			for _, r := range roots {
+				rw, rr := sphereOf(r.Value)
+				found = found || math.Abs(rw-w) < 1e-9 && math.Abs(rr-radius) < 1e-9
+			}
+			if !found {
+				t.Errorf("Wrong roots for %v. Expected one on the sphere of %v among %v", q, f, roots)
+			}
+		}
+	}
+}
+
+func TestPolynomial_Roots_Spherical(t *testing.T) {
+	one := NewQuaternionByCoords(1, 0, 0, 0)
+	tests := []struct {
+		name      string
+		p         *Polynomial
+		isolated  []*Quaternion
+		spherical []*Quaternion
+	}{
+		{
+			name:      "x² + 1",
+			p:         NewPolynomial(one, NewQuaternionByCoords(0, 0, 0, 0), one),
+			spherical: []*Quaternion{NewQuaternionByCoords(0, 1, 0, 0)},
+		},
+		{
+			name:      "(x² - 2x + 5)(x - j)",
+			p:         NewPolynomial(NewQuaternionByCoords(5, 0, 0, 0), NewQuaternionByCoords(-2, 0, 0, 0), one).Mul(linearFactors(NewQuaternionByCoords(0, 0, 1, 0))),
+			isolated:  []*Quaternion{NewQuaternionByCoords(0, 0, 1, 0)},
+			spherical: []*Quaternion{NewQuaternionByCoords(1, 2, 0, 0)},
+		},
+		{
+			name:     "(x - 2)(x - 3k)",
+			p:        linearFactors(NewQuaternionByCoords(2, 0, 0, 0), NewQuaternionByCoords(0, 0, 0, 3)),
+			isolated: []*Quaternion{NewQuaternionByCoords(0, 0, 0, 3), NewQuaternionByCoords(2, 0, 0, 0)},
+		},
+		{
+			name:      "(x - i)(x + i)",
+			p:         linearFactors(NewQuaternionByCoords(0, 1, 0, 0), NewQuaternionByCoords(0, -1, 0, 0)),
+			spherical: []*Quaternion{NewQuaternionByCoords(0, 1, 0, 0)},
+		},
+		{
+			name:     "(x - 1)³",
+			p:        linearFactors(one, one, one),
+			isolated: []*Quaternion{one},
+		},
+		{
+			name:     "(x - i)(x - j)(x + k)",
+			p:        linearFactors(NewQuaternionByCoords(0, 1, 0, 0), NewQuaternionByCoords(0, 0, 1, 0), NewQuaternionByCoords(0, 0, 0, -1)),
+			isolated: []*Quaternion{NewQuaternionByCoords(0, 0, 0, -1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, err := tt.p.Roots()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var isolated, spherical []*Quaternion
+			for _, r := range roots {
+				if r.Spherical {
+					spherical = append(spherical, r.Value)
+				} else {
+					isolated = append(isolated, r.Value)
+				}
+			}
+			if len(isolated) != len(tt.isolated) || len(spherical) != len(tt.spherical) {
+				t.Fatalf("Wrong roots. Expected %v and spheres %v, got %v", tt.isolated, tt.spherical, roots)
+			}
+			for n := range isolated {
+				if !isolated[n].equalsByCoords(tt.isolated[n], 1e-5) {
+					t.Errorf("Wrong isolated root. Expected %v, got %v", tt.isolated[n], isolated[n])
+				}
+			}
+			for n := range spherical {
+				if !spherical[n].equalsByCoords(tt.spherical[n], 1e-7) {
+					t.Errorf("Wrong spherical root. Expected %v, got %v", tt.spherical[n], spherical[n])
+				}
+				// Every point of the sphere is a root.
+				w, radius := sphereOf(spherical[n])
+				u, _ := NewQuaternionByCoords(0, rand.Float64()-0.5, rand.Float64()-0.5, rand.Float64()-0.5).Normalize()
+				x := NewQuaternionByCoords(w, 0, 0, 0).Add(u.MulByNumber(radius))
+				if got := tt.p.Evaluate(x); math.Sqrt(got.Norm()) > 1e-7 {
+					t.Errorf("Wrong spherical root %v. Value at %v is %v", spherical[n], x, got)
+				}
+			}
+		})
+	}
+
+	if _, err := NewPolynomial(NewQuaternionByCoords(0, 0, 0, 0)).Roots(); err == nil {
+		t.Errorf("Expected error for roots of zero polynomial")
+	}
+	if roots, err := NewPolynomial(one).Roots(); err != nil || len(roots) != 0 {
+		t.Errorf("Wrong roots of constant. Expected none, got %v, %v", roots, err)
+	}
+}
@@ -0,0 +1,175 @@
+package posegraph
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var MalformedG2OError = errors.WithStack(errors.New("Malformed g2o file"))
+
+// g2oRotationScale maps the g2o rotation error, the vector part of the error
+// quaternion, to the rotation vector used here, which is about twice as long.
+const g2oRotationScale = 0.5
+
+// ReadG2O parses VERTEX_SE3:QUAT, EDGE_SE3:QUAT and FIX lines. Quaternions
+// are stored as qx qy qz qw and information matrices as the 21 entries of
+// their upper triangle; the rotation rows are rescaled to the [ρ; φ] error.
+func ReadG2O(r io.Reader) (*Graph, error) {
+	g := NewGraph()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if err := readG2OLine(g, fields); err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func readG2OLine(g *Graph, fields []string) error {
+	switch fields[0] {
+	case "VERTEX_SE3:QUAT":
+		if len(fields) != 9 {
+			return errors.Wrapf(MalformedG2OError, "expected 8 values for vertex, got %d", len(fields)-1)
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return errors.Wrap(MalformedG2OError, err.Error())
+		}
+		v, err := parseFloats(fields[2:])
+		if err != nil {
+			return err
+		}
+		pose, err := g2oPose(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = g.AddNode(id, pose)
+		return err
+
+	case "EDGE_SE3:QUAT":
+		if len(fields) != 31 {
+			return errors.Wrapf(MalformedG2OError, "expected 30 values for edge, got %d", len(fields)-1)
+		}
+		from, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return errors.Wrap(MalformedG2OError, err.Error())
+		}
+		to, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return errors.Wrap(MalformedG2OError, err.Error())
+		}
+		v, err := parseFloats(fields[3:])
+		if err != nil {
+			return err
+		}
+		pose, err := g2oPose(v[:7])
+		if err != nil {
+			return err
+		}
+
+		var info lie.Mat6
+		k := 7
+		for r := 0; r < 6; r++ {
+			for c := r; c < 6; c++ {
+				info[r][c] = v[k] * g2oScale(r) * g2oScale(c)
+				info[c][r] = info[r][c]
+				k++
+			}
+		}
+
+		_, err = g.AddEdge(from, to, pose, info)
+		return err
+
+	case "FIX":
+		for _, f := range fields[1:] {
+			id, err := strconv.Atoi(f)
+			if err != nil {
+				return errors.Wrap(MalformedG2OError, err.Error())
+			}
+			n, err := g.Node(id)
+			if err != nil {
+				return err
+			}
+			n.Fixed = true
+		}
+		return nil
+	}
+
+	return errors.Wrapf(MalformedG2OError, "unsupported tag %s", fields[0])
+}
+
+func WriteG2O(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(bw, "VERTEX_SE3:QUAT %d %s\n", n.ID, formatG2OPose(n.Pose))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "EDGE_SE3:QUAT %d %d %s", e.From, e.To, formatG2OPose(e.Measurement))
+		for r := 0; r < 6; r++ {
+			for c := r; c < 6; c++ {
+				fmt.Fprintf(bw, " %v", e.Information[r][c]/(g2oScale(r)*g2oScale(c)))
+			}
+		}
+		fmt.Fprintln(bw)
+	}
+	for _, n := range g.Nodes {
+		if n.Fixed {
+			fmt.Fprintf(bw, "FIX %d\n", n.ID)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func g2oScale(row int) float64 {
+	if row < 3 {
+		return 1
+	}
+
+	return g2oRotationScale
+}
+
+func g2oPose(v []float64) (*quat.BQuaternion, error) {
+	rotation, err := quat.NewQuaternionByCoords(v[6], v[3], v[4], v[5]).Normalize()
+	if err != nil {
+		return nil, errors.Wrap(MalformedG2OError, err.Error())
+	}
+
+	return quat.NewRigidBQuaternion(rotation, &quat.Vec3{X: v[0], Y: v[1], Z: v[2]}), nil
+}
+
+func formatG2OPose(bq *quat.BQuaternion) string {
+	t := bq.Translation()
+
+	return fmt.Sprintf("%v %v %v %v %v %v %v", t.X, t.Y, t.Z, bq.P.I, bq.P.J, bq.P.K, bq.P.W)
+}
+
+func parseFloats(fields []string) ([]float64, error) {
+	v := make([]float64, len(fields))
+	for n, f := range fields {
+		var err error
+		if v[n], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, errors.Wrap(MalformedG2OError, err.Error())
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,76 @@
+package posegraph
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+const g2oSample = `# three poses
+VERTEX_SE3:QUAT 0 0 0 0 0 0 0 1
+VERTEX_SE3:QUAT 1 1 0 0 0 0 0.7071067811865476 0.7071067811865476
+VERTEX_SE3:QUAT 2 1 1 0 0 0 1 0
+EDGE_SE3:QUAT 0 1 1 0 0 0 0 0.7071067811865476 0.7071067811865476 1 0 0 0 0 0 1 0 0 0 0 1 0 0 0 4 0 0 4 0 4
+EDGE_SE3:QUAT 1 2 1 0 0 0 0 0.7071067811865476 0.7071067811865476 1 0 0 0 0 0 1 0 0 0 0 1 0 0 0 4 0 0 4 0 4
+FIX 0
+`
+
+func TestReadG2O(t *testing.T) {
+	g, err := ReadG2O(strings.NewReader(g2oSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(g.Nodes) != 3 || len(g.Edges) != 2 || !g.Nodes[0].Fixed {
+		t.Fatalf("Wrong graph: %v nodes, %v edges", len(g.Nodes), len(g.Edges))
+	}
+	if want := 1.0; math.Abs(g.Edges[0].Information[3][3]-want) > 1e-15 {
+		t.Errorf("Wrong rotation information. Expected %v, got %v", want, g.Edges[0].Information[3][3])
+	}
+	for _, e := range g.Edges {
+		r, err := g.Residual(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range r {
+			if math.Abs(v) > 1e-12 {
+				t.Errorf("Expected consistent edge %v->%v, residual %v", e.From, e.To, r)
+			}
+		}
+	}
+}
+
+func TestWriteG2O_ShouldRoundTrip(t *testing.T) {
+	g, err := ReadG2O(strings.NewReader(g2oSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteG2O(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadG2O(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, n := range got.Nodes {
+		if !n.Pose.Equals(g.Nodes[k].Pose, 1e-15) || n.Fixed != g.Nodes[k].Fixed {
+			t.Errorf("Wrong node %v. Expected %v, got %v", k, g.Nodes[k].Pose, n.Pose)
+		}
+	}
+	for k, e := range got.Edges {
+		if e.Information != g.Edges[k].Information || !e.Measurement.Equals(g.Edges[k].Measurement, 1e-15) {
+			t.Errorf("Wrong edge %v", k)
+		}
+	}
+}
+
+func TestReadG2O_ShouldReportLine(t *testing.T) {
+	_, err := ReadG2O(strings.NewReader("VERTEX_SE3:QUAT 0 0 0 0 0 0 0 1\nEDGE_SE3:QUAT 0 1 1 2\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected error on line 2, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+// Package posegraph optimizes graphs of BQuaternion poses linked by relative
+// pose measurements.
+//
+// The residual of an edge from i to j with measurement Z is
+// e = Log(Z⁻¹ * Xi⁻¹ * Xj) in the [ρ; φ] tangent of package lie, and poses
+// are updated on the right, X ← X * Exp(δ).
+package posegraph
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+)
+
+var (
+	DuplicateNodeError = errors.WithStack(errors.New("Node already exists"))
+	UnknownNodeError   = errors.WithStack(errors.New("Unknown node"))
+)
+
+type Node struct {
+	ID    int
+	Pose  *quat.BQuaternion
+	Fixed bool
+}
+
+type Edge struct {
+	From, To    int
+	Measurement *quat.BQuaternion
+	Information lie.Mat6
+}
+
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	index map[int]int
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		index: map[int]int{},
+	}
+}
+
+func (g *Graph) AddNode(id int, pose *quat.BQuaternion) (*Node, error) {
+	if _, ok := g.index[id]; ok {
+		return nil, errors.Wrapf(DuplicateNodeError, "node %d", id)
+	}
+
+	n := &Node{ID: id, Pose: pose}
+	g.index[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+
+	return n, nil
+}
+
+func (g *Graph) Node(id int) (*Node, error) {
+	n, ok := g.index[id]
+	if !ok {
+		return nil, errors.Wrapf(UnknownNodeError, "node %d", id)
+	}
+
+	return g.Nodes[n], nil
+}
+
+func (g *Graph) AddEdge(from, to int, measurement *quat.BQuaternion, information lie.Mat6) (*Edge, error) {
+	if _, err := g.Node(from); err != nil {
+		return nil, err
+	}
+	if _, err := g.Node(to); err != nil {
+		return nil, err
+	}
+
+	e := &Edge{From: from, To: to, Measurement: measurement, Information: information}
+	g.Edges = append(g.Edges, e)
+
+	return e, nil
+}
+
+// Residual returns the error of e for the current node poses.
+func (g *Graph) Residual(e *Edge) (lie.Vec6, error) {
+	from, err := g.Node(e.From)
+	if err != nil {
+		return lie.Vec6{}, err
+	}
+	to, err := g.Node(e.To)
+	if err != nil {
+		return lie.Vec6{}, err
+	}
+
+	return lie.SE3Log(e.Measurement.ComplexConjugate().Mul(from.Pose.ComplexConjugate()).Mul(to.Pose))
+}
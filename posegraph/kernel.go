@@ -0,0 +1,44 @@
+package posegraph
+
+import "math"
+
+// Kernel is a robust loss applied to the squared Mahalanobis error s of an
+// edge. Weight is the derivative of Cost, used to reweight the edge.
+type Kernel interface {
+	Cost(s float64) float64
+	Weight(s float64) float64
+}
+
+type Huber struct {
+	Delta float64
+}
+
+func (k Huber) Cost(s float64) float64 {
+	if s <= k.Delta*k.Delta {
+		return s
+	}
+
+	return 2*k.Delta*math.Sqrt(s) - k.Delta*k.Delta
+}
+
+func (k Huber) Weight(s float64) float64 {
+	if s <= k.Delta*k.Delta {
+		return 1
+	}
+
+	return k.Delta / math.Sqrt(s)
+}
+
+type Cauchy struct {
+	C float64
+}
+
+func (k Cauchy) Cost(s float64) float64 {
+	c2 := k.C * k.C
+
+	return c2 * math.Log1p(s/c2)
+}
+
+func (k Cauchy) Weight(s float64) float64 {
+	return 1 / (1 + s/(k.C*k.C))
+}
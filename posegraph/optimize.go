@@ -0,0 +1,284 @@
+package posegraph
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+)
+
+var NoFreeNodesError = errors.WithStack(errors.New("Graph has no free nodes"))
+
+type Method int
+
+const (
+	GaussNewton Method = iota
+	LevenbergMarquardt
+)
+
+type Options struct {
+	Method        Method
+	MaxIterations int
+	// Tolerance stops the optimization once the largest update component
+	// or the relative cost decrease falls below it.
+	Tolerance float64
+	// Kernel is the robust loss; nil means plain least squares.
+	Kernel Kernel
+	// Lambda is the initial Levenberg-Marquardt damping.
+	Lambda float64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Method:        LevenbergMarquardt,
+		MaxIterations: 100,
+		Tolerance:     1e-10,
+		Lambda:        1e-4,
+	}
+}
+
+type Result struct {
+	Iterations  int
+	InitialCost float64
+	FinalCost   float64
+	// Converged is set when the update or the cost decrease fell below the
+	// tolerance. It stays false when the iterations ran out or when no step
+	// lowers the cost: a Gauss-Newton step that overshoots, or
+	// Levenberg-Marquardt damping past its limit. The poses are then those
+	// of the last accepted step.
+	Converged bool
+}
+
+// Optimize refines the poses of all nodes that are not Fixed. When no node
+// is fixed, the first node is held in place to remove the gauge freedom.
+func (g *Graph) Optimize(opts Options) (*Result, error) {
+	vars := g.variables()
+	if len(vars.free) == 0 {
+		return nil, NoFreeNodesError
+	}
+
+	cost, err := g.cost(opts.Kernel)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{InitialCost: cost, FinalCost: cost}
+	lambda := opts.Lambda
+
+	for res.Iterations < opts.MaxIterations {
+		res.Iterations++
+
+		h, b, err := g.linearize(vars, opts.Kernel)
+		if err != nil {
+			return nil, err
+		}
+
+		for {
+			system := h
+			if opts.Method == LevenbergMarquardt {
+				system = h.clone()
+				system.damp(lambda)
+			}
+			if err := system.cholesky(); err != nil {
+				if opts.Method == LevenbergMarquardt && lambda < 1e10 {
+					lambda *= 10
+					continue
+				}
+				return nil, err
+			}
+
+			delta := system.solve(b)
+			old := g.apply(vars, delta)
+
+			newCost, err := g.cost(opts.Kernel)
+			if err != nil {
+				return nil, err
+			}
+
+			if newCost > cost {
+				g.restore(vars, old)
+				if maxAbs(delta) < opts.Tolerance {
+					// The rejected step is below the tolerance, so the
+					// increase is rounding at the minimum.
+					res.Converged = true
+					return res, nil
+				}
+				if opts.Method == GaussNewton {
+					// Gauss-Newton has no damping to shorten the step.
+					return res, nil
+				}
+				lambda *= 10
+				if lambda > 1e10 {
+					// No damping lowers the cost; the graph is stuck, not
+					// converged.
+					return res, nil
+				}
+				continue
+			}
+			lambda = math.Max(lambda/10, 1e-12)
+
+			decrease := cost - newCost
+			cost = newCost
+			res.FinalCost = cost
+			if maxAbs(delta) < opts.Tolerance || decrease >= 0 && decrease <= opts.Tolerance*cost {
+				res.Converged = true
+				return res, nil
+			}
+			break
+		}
+	}
+
+	return res, nil
+}
+
+type variables struct {
+	free  []*Node
+	index map[int]int
+}
+
+func (g *Graph) variables() *variables {
+	fixed := false
+	for _, n := range g.Nodes {
+		fixed = fixed || n.Fixed
+	}
+
+	vars := &variables{index: map[int]int{}}
+	for k, n := range g.Nodes {
+		if n.Fixed || !fixed && k == 0 {
+			continue
+		}
+		vars.index[n.ID] = len(vars.free)
+		vars.free = append(vars.free, n)
+	}
+
+	return vars
+}
+
+func (g *Graph) cost(kernel Kernel) (float64, error) {
+	var cost float64
+	for _, e := range g.Edges {
+		r, err := g.Residual(e)
+		if err != nil {
+			return 0, err
+		}
+
+		s := mahalanobis(r, &e.Information)
+		if kernel != nil {
+			s = kernel.Cost(s)
+		}
+		cost += s
+	}
+
+	return cost, nil
+}
+
+// linearize builds the normal equations H*δ = b of the reweighted problem.
+func (g *Graph) linearize(vars *variables, kernel Kernel) (*blockMatrix, []lie.Vec6, error) {
+	h := newBlockMatrix(len(vars.free))
+	b := make([]lie.Vec6, len(vars.free))
+
+	for _, e := range g.Edges {
+		r, err := g.Residual(e)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		w := 1.0
+		if kernel != nil {
+			w = kernel.Weight(mahalanobis(r, &e.Information))
+		}
+
+		from, _ := g.Node(e.From)
+		to, _ := g.Node(e.To)
+
+		jrInv := lie.SE3RightJacobianInverse(r)
+		ad := lie.SE3Adjoint(to.Pose.ComplexConjugate().Mul(from.Pose))
+		jFrom := scale6(jrInv.Mul(ad), -1)
+		jTo := jrInv
+
+		i, iFree := vars.index[e.From]
+		j, jFree := vars.index[e.To]
+		omega := scale6(e.Information, w)
+
+		if iFree {
+			ja := jFrom.Transpose().Mul(omega)
+			h.add(i, i, ja.Mul(jFrom))
+			addVec6(&b[i], ja.MulVec(r), -1)
+			if jFree {
+				h.add(i, j, ja.Mul(jTo))
+			}
+		}
+		if jFree {
+			jb := jTo.Transpose().Mul(omega)
+			h.add(j, j, jb.Mul(jTo))
+			addVec6(&b[j], jb.MulVec(r), -1)
+		}
+	}
+
+	for k := range h.cols {
+		if _, ok := h.cols[k][k]; !ok {
+			return nil, nil, errors.Wrapf(NotPositiveDefiniteError, "node %d has no edges", vars.free[k].ID)
+		}
+	}
+
+	return h, b, nil
+}
+
+func (g *Graph) apply(vars *variables, delta []lie.Vec6) []*quat.BQuaternion {
+	old := make([]*quat.BQuaternion, len(vars.free))
+	for k, n := range vars.free {
+		old[k] = n.Pose
+		p := n.Pose.Mul(lie.SE3Exp(delta[k]))
+
+		rotation, err := p.P.Normalize()
+		if err != nil {
+			continue
+		}
+		n.Pose = quat.NewRigidBQuaternion(rotation, p.Translation())
+	}
+
+	return old
+}
+
+func (g *Graph) restore(vars *variables, old []*quat.BQuaternion) {
+	for k, n := range vars.free {
+		n.Pose = old[k]
+	}
+}
+
+func mahalanobis(r lie.Vec6, information *lie.Mat6) float64 {
+	v := information.MulVec(r)
+
+	var s float64
+	for n := range r {
+		s += r[n] * v[n]
+	}
+
+	return s
+}
+
+func scale6(m lie.Mat6, s float64) lie.Mat6 {
+	for r := range m {
+		for c := range m[r] {
+			m[r][c] *= s
+		}
+	}
+
+	return m
+}
+
+func addVec6(dst *lie.Vec6, v lie.Vec6, s float64) {
+	for n := range dst {
+		dst[n] += s * v[n]
+	}
+}
+
+func maxAbs(delta []lie.Vec6) float64 {
+	var m float64
+	for _, d := range delta {
+		for _, v := range d {
+			m = math.Max(m, math.Abs(v))
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,242 @@
+package posegraph
+
+import (
+	quat "go-quaternions"
+	"go-quaternions/lie"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func circlePoses(n int) []*quat.BQuaternion {
+	poses := make([]*quat.BQuaternion, n)
+	for k := range poses {
+		a := 2 * math.Pi * float64(k) / float64(n)
+		poses[k] = lie.SE3Exp(lie.Vec6{0, 0, 0, 0.1 * math.Sin(a), 0, a}).
+			Mul(quat.NewRigidBQuaternion(quat.NewQuaternionByCoords(1, 0, 0, 0), &quat.Vec3{X: 5, Z: math.Cos(a)}))
+	}
+
+	return poses
+}
+
+func relative(a, b *quat.BQuaternion) *quat.BQuaternion {
+	return a.ComplexConjugate().Mul(b)
+}
+
+func scaledIdentity(s float64) lie.Mat6 {
+	m := lie.Identity6()
+	for k := range m {
+		m[k][k] = s
+	}
+
+	return m
+}
+
+// noisyGraph builds a circle with exact odometry and loop closures and
+// perturbs every pose but the first.
+func noisyGraph(t *testing.T, truth []*quat.BQuaternion, noise float64) *Graph {
+	return perturbedGraph(t, truth, noise, rand.NormFloat64)
+}
+
+func perturbedGraph(t *testing.T, truth []*quat.BQuaternion, noise float64, normal func() float64) *Graph {
+	g := NewGraph()
+	for k, pose := range truth {
+		if k > 0 {
+			var d lie.Vec6
+			for m := range d {
+				d[m] = noise * normal()
+			}
+			pose = pose.Mul(lie.SE3Exp(d))
+		}
+		if _, err := g.AddNode(k, pose); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edges := [][2]int{{0, len(truth) - 1}, {3, 11}, {5, 15}}
+	for k := 0; k+1 < len(truth); k++ {
+		edges = append(edges, [2]int{k, k + 1})
+	}
+	for _, e := range edges {
+		if _, err := g.AddEdge(e[0], e[1], relative(truth[e[0]], truth[e[1]]), scaledIdentity(100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+func maxPoseError(t *testing.T, g *Graph, truth []*quat.BQuaternion) float64 {
+	var m float64
+	for k, n := range g.Nodes {
+		d, err := n.Pose.Distance(truth[k], 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m = math.Max(m, d)
+	}
+
+	return m
+}
+
+func TestGraph_Optimize(t *testing.T) {
+	tests := []struct {
+		name   string
+		method Method
+		kernel Kernel
+		noise  float64
+	}{
+		{name: "gauss newton", method: GaussNewton, noise: 0.05},
+		{name: "levenberg marquardt", method: LevenbergMarquardt, noise: 0.2},
+		{name: "huber", method: LevenbergMarquardt, kernel: Huber{Delta: 1}, noise: 0.1},
+		{name: "cauchy", method: LevenbergMarquardt, kernel: Cauchy{C: 1}, noise: 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			truth := circlePoses(20)
+			g := noisyGraph(t, truth, tt.noise)
+
+			opts := DefaultOptions()
+			opts.Method = tt.method
+			opts.Kernel = tt.kernel
+
+			res, err := g.Optimize(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Converged || res.FinalCost > 1e-12 || res.FinalCost > res.InitialCost {
+				t.Errorf("Expected convergence to zero cost, got %+v", res)
+			}
+			if e := maxPoseError(t, g, truth); e > 1e-6 {
+				t.Errorf("Wrong optimized poses, max error %v", e)
+			}
+		})
+	}
+}
+
+func TestGraph_Optimize_RobustKernelShouldSuppressOutlier(t *testing.T) {
+	truth := circlePoses(20)
+	errs := map[string]float64{}
+
+	for name, kernel := range map[string]Kernel{"none": nil, "cauchy": Cauchy{C: 1}} {
+		g := noisyGraph(t, truth, 0.01)
+		outlier := lie.SE3Exp(lie.Vec6{3, -2, 1, 0.5, 0.5, -1})
+		if _, err := g.AddEdge(2, 9, outlier, scaledIdentity(100)); err != nil {
+			t.Fatal(err)
+		}
+
+		opts := DefaultOptions()
+		opts.Kernel = kernel
+		if _, err := g.Optimize(opts); err != nil {
+			t.Fatal(err)
+		}
+		errs[name] = maxPoseError(t, g, truth)
+	}
+
+	if errs["cauchy"] > 5e-2 || errs["cauchy"]*10 > errs["none"] {
+		t.Errorf("Expected Cauchy kernel to suppress the outlier, got errors %v", errs)
+	}
+}
+
+func TestGraph_Optimize_GaussNewtonShouldRejectOvershoot(t *testing.T) {
+	// The first Gauss-Newton step from this guess raises the cost.
+	g := perturbedGraph(t, circlePoses(20), 1, rand.New(rand.NewSource(1)).NormFloat64)
+	before := make([]*quat.BQuaternion, len(g.Nodes))
+	for k, n := range g.Nodes {
+		before[k] = n.Pose
+	}
+
+	opts := DefaultOptions()
+	opts.Method = GaussNewton
+	res, err := g.Optimize(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Converged || res.FinalCost != res.InitialCost {
+		t.Errorf("Expected no convergence at the initial cost, got %+v", res)
+	}
+	for k, n := range g.Nodes {
+		if *n.Pose.P != *before[k].P || *n.Pose.Q != *before[k].Q {
+			t.Errorf("Wrong pose of node %v after rejected step. Expected %v, got %v", k, before[k], n.Pose)
+		}
+	}
+}
+
+func TestGraph_Optimize_ShouldKeepFixedNodes(t *testing.T) {
+	truth := circlePoses(20)
+	g := noisyGraph(t, truth, 0.05)
+	g.Nodes[0].Fixed = false
+	g.Nodes[4].Fixed = true
+	fixed := g.Nodes[4].Pose
+
+	if _, err := g.Optimize(DefaultOptions()); err != nil {
+		t.Fatal(err)
+	}
+	if g.Nodes[4].Pose != fixed {
+		t.Errorf("Fixed node moved to %v", g.Nodes[4].Pose)
+	}
+}
+
+func TestGraph_AddEdge_ShouldFailForUnknownNode(t *testing.T) {
+	g := NewGraph()
+	if _, err := g.AddNode(1, lie.SE3Exp(lie.Vec6{})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.AddNode(1, lie.SE3Exp(lie.Vec6{})); err == nil {
+		t.Errorf("Expected error for duplicate node")
+	}
+	if _, err := g.AddEdge(1, 2, lie.SE3Exp(lie.Vec6{}), lie.Identity6()); err == nil {
+		t.Errorf("Expected error for unknown node")
+	}
+}
+
+func TestBlockMatrix_Solve(t *testing.T) {
+	const n = 8
+	var dense [n * 6][n * 6]float64
+	m := newBlockMatrix(n)
+
+	pairs := [][2]int{{1, 0}, {7, 0}, {4, 2}, {6, 3}, {5, 1}}
+	for _, p := range pairs {
+		var b lie.Mat6
+		for r := range b {
+			for c := range b[r] {
+				b[r][c] = rand.NormFloat64()
+			}
+		}
+		m.add(p[0], p[1], b)
+		for r := 0; r < 6; r++ {
+			for c := 0; c < 6; c++ {
+				dense[p[0]*6+r][p[1]*6+c] += b[r][c]
+				dense[p[1]*6+c][p[0]*6+r] += b[r][c]
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		m.add(k, k, scaledIdentity(50))
+		for r := 0; r < 6; r++ {
+			dense[k*6+r][k*6+r] += 50
+		}
+	}
+
+	b := make([]lie.Vec6, n)
+	for k := range b {
+		for r := range b[k] {
+			b[k][r] = rand.NormFloat64()
+		}
+	}
+
+	if err := m.cholesky(); err != nil {
+		t.Fatal(err)
+	}
+	x := m.solve(b)
+
+	for r := 0; r < n*6; r++ {
+		var s float64
+		for c := 0; c < n*6; c++ {
+			s += dense[r][c] * x[c/6][c%6]
+		}
+		if math.Abs(s-b[r/6][r%6]) > 1e-12 {
+			t.Errorf("Wrong solution at row %v. Expected %v, got %v", r, b[r/6][r%6], s)
+		}
+	}
+}
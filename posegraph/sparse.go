@@ -0,0 +1,209 @@
+package posegraph
+
+import (
+	"github.com/pkg/errors"
+	"go-quaternions/lie"
+	"math"
+	"sort"
+)
+
+var NotPositiveDefiniteError = errors.WithStack(errors.New("System matrix is not positive definite"))
+
+// blockMatrix is a symmetric matrix of 6x6 blocks storing only the lower
+// triangle, column by column: cols[j][i] holds block (i, j) for i >= j.
+type blockMatrix struct {
+	cols []map[int]*lie.Mat6
+}
+
+func newBlockMatrix(n int) *blockMatrix {
+	m := &blockMatrix{cols: make([]map[int]*lie.Mat6, n)}
+	for j := range m.cols {
+		m.cols[j] = map[int]*lie.Mat6{}
+	}
+
+	return m
+}
+
+// add accumulates b into block (i, j) and, implicitly, its transpose.
+func (m *blockMatrix) add(i, j int, b lie.Mat6) {
+	if i < j {
+		i, j = j, i
+		b = b.Transpose()
+	}
+
+	dst, ok := m.cols[j][i]
+	if !ok {
+		dst = &lie.Mat6{}
+		m.cols[j][i] = dst
+	}
+	for r := range dst {
+		for c := range dst[r] {
+			dst[r][c] += b[r][c]
+		}
+	}
+}
+
+func (m *blockMatrix) clone() *blockMatrix {
+	res := newBlockMatrix(len(m.cols))
+	for j, col := range m.cols {
+		for i, b := range col {
+			c := *b
+			res.cols[j][i] = &c
+		}
+	}
+
+	return res
+}
+
+// damp scales the diagonal by 1+lambda, as in Levenberg-Marquardt.
+func (m *blockMatrix) damp(lambda float64) {
+	for j, col := range m.cols {
+		d := col[j]
+		for k := range d {
+			d[k][k] *= 1 + lambda
+		}
+	}
+}
+
+// cholesky replaces the matrix by its lower Cholesky factor L, A = L*Lᵀ,
+// in the natural block order. Fill-in blocks are created as needed.
+func (m *blockMatrix) cholesky() error {
+	for j, col := range m.cols {
+		d, ok := col[j]
+		if !ok {
+			return NotPositiveDefiniteError
+		}
+		l, err := cholesky6(d)
+		if err != nil {
+			return err
+		}
+		*d = l
+
+		rows := make([]int, 0, len(col))
+		for i := range col {
+			if i > j {
+				rows = append(rows, i)
+			}
+		}
+		sort.Ints(rows)
+
+		for _, i := range rows {
+			*col[i] = solveRightTransposed(col[i], &l)
+		}
+
+		for a, i := range rows {
+			for _, k := range rows[:a+1] {
+				m.add(i, k, mulTransposed(col[i], col[k], -1))
+			}
+		}
+	}
+
+	return nil
+}
+
+// solve returns x with L*Lᵀ*x = b for a factorized matrix.
+func (m *blockMatrix) solve(b []lie.Vec6) []lie.Vec6 {
+	y := make([]lie.Vec6, len(b))
+	copy(y, b)
+
+	for j, col := range m.cols {
+		y[j] = forward6(col[j], y[j])
+		for i, l := range col {
+			if i > j {
+				v := l.MulVec(y[j])
+				for r := range v {
+					y[i][r] -= v[r]
+				}
+			}
+		}
+	}
+
+	for j := len(m.cols) - 1; j >= 0; j-- {
+		for i, l := range m.cols[j] {
+			if i > j {
+				v := l.Transpose().MulVec(y[i])
+				for r := range v {
+					y[j][r] -= v[r]
+				}
+			}
+		}
+		y[j] = backward6(m.cols[j][j], y[j])
+	}
+
+	return y
+}
+
+func cholesky6(a *lie.Mat6) (lie.Mat6, error) {
+	var l lie.Mat6
+	for r := 0; r < 6; r++ {
+		for c := 0; c <= r; c++ {
+			s := a[r][c]
+			for k := 0; k < c; k++ {
+				s -= l[r][k] * l[c][k]
+			}
+
+			if r == c {
+				if s <= 0 || math.IsNaN(s) {
+					return l, NotPositiveDefiniteError
+				}
+				l[r][r] = math.Sqrt(s)
+			} else {
+				l[r][c] = s / l[c][c]
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// solveRightTransposed returns X with X*Lᵀ = A.
+func solveRightTransposed(a, l *lie.Mat6) lie.Mat6 {
+	var x lie.Mat6
+	for r := range x {
+		x[r] = forward6(l, a[r])
+	}
+
+	return x
+}
+
+// mulTransposed returns s*A*Bᵀ.
+func mulTransposed(a, b *lie.Mat6, s float64) lie.Mat6 {
+	var res lie.Mat6
+	for r := range res {
+		for c := range res[r] {
+			var v float64
+			for k := 0; k < 6; k++ {
+				v += a[r][k] * b[c][k]
+			}
+			res[r][c] = s * v
+		}
+	}
+
+	return res
+}
+
+func forward6(l *lie.Mat6, b lie.Vec6) lie.Vec6 {
+	var x lie.Vec6
+	for r := range x {
+		s := b[r]
+		for k := 0; k < r; k++ {
+			s -= l[r][k] * x[k]
+		}
+		x[r] = s / l[r][r]
+	}
+
+	return x
+}
+
+func backward6(l *lie.Mat6, b lie.Vec6) lie.Vec6 {
+	var x lie.Vec6
+	for r := 5; r >= 0; r-- {
+		s := b[r]
+		for k := r + 1; k < 6; k++ {
+			s -= l[k][r] * x[k]
+		}
+		x[r] = s / l[r][r]
+	}
+
+	return x
+}
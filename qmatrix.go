@@ -0,0 +1,431 @@
+package go_quaternions
+
+import (
+	"github.com/pkg/errors"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strings"
+)
+
+const (
+	jacobiSweeps = 60
+	// jacobiTolerance is the cosine between two columns below which they
+	// count as orthogonal, and the relative size of a zero singular value.
+	jacobiTolerance = 1e-15
+	// repeatedEigenvalueTolerance is the relative distance below which two
+	// computed eigenvalues count as copies of one, and the relative
+	// imaginary part below which an eigenvalue counts as real.
+	repeatedEigenvalueTolerance = 1e-8
+)
+
+var (
+	DimensionMismatchError = errors.WithStack(errors.New("Matrix dimensions do not match"))
+	NotSquareMatrixError   = errors.WithStack(errors.New("Matrix is not square"))
+	SingularMatrixError    = errors.WithStack(errors.New("Matrix is singular"))
+)
+
+// QMatrix is a dense matrix of quaternions stored row by row. Since the
+// entries do not commute, Transpose does not reverse products; the
+// conjugate transpose does.
+type QMatrix struct {
+	Rows, Cols int
+	Data       []Quaternion
+}
+
+// NewQMatrix returns a zero matrix.
+func NewQMatrix(rows, cols int) *QMatrix {
+	return &QMatrix{Rows: rows, Cols: cols, Data: make([]Quaternion, rows*cols)}
+}
+
+// NewQMatrixFromRows copies the rows, which must have equal lengths.
+func NewQMatrixFromRows(rows ...[]*Quaternion) (*QMatrix, error) {
+	if len(rows) == 0 {
+		return NewQMatrix(0, 0), nil
+	}
+
+	m := NewQMatrix(len(rows), len(rows[0]))
+	for r, row := range rows {
+		if len(row) != m.Cols {
+			return nil, errors.Wrapf(DimensionMismatchError, "row %d has %d entries, expected %d", r, len(row), m.Cols)
+		}
+		for c, q := range row {
+			m.Set(r, c, q)
+		}
+	}
+
+	return m, nil
+}
+
+func NewIdentityQMatrix(n int) *QMatrix {
+	m := NewQMatrix(n, n)
+	for k := 0; k < n; k++ {
+		m.Data[k*n+k].W = 1
+	}
+
+	return m
+}
+
+// At returns a copy of the entry in row r and column c.
+func (m *QMatrix) At(r, c int) *Quaternion {
+	q := m.Data[r*m.Cols+c]
+
+	return &q
+}
+
+func (m *QMatrix) Set(r, c int, q *Quaternion) {
+	m.Data[r*m.Cols+c] = *q
+}
+
+func (m *QMatrix) Add(arg *QMatrix) (*QMatrix, error) {
+	if m.Rows != arg.Rows || m.Cols != arg.Cols {
+		return nil, errors.Wrapf(DimensionMismatchError, "%dx%d + %dx%d", m.Rows, m.Cols, arg.Rows, arg.Cols)
+	}
+
+	sum := NewQMatrix(m.Rows, m.Cols)
+	for n := range sum.Data {
+		sum.Data[n] = *m.Data[n].Add(&arg.Data[n])
+	}
+
+	return sum, nil
+}
+
+func (m *QMatrix) Mul(arg *QMatrix) (*QMatrix, error) {
+	if m.Cols != arg.Rows {
+		return nil, errors.Wrapf(DimensionMismatchError, "%dx%d * %dx%d", m.Rows, m.Cols, arg.Rows, arg.Cols)
+	}
+
+	product := NewQMatrix(m.Rows, arg.Cols)
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < arg.Cols; c++ {
+			sum := NewQuaternionByCoords(0, 0, 0, 0)
+			for k := 0; k < m.Cols; k++ {
+				sum = sum.Add(m.At(r, k).MulByGrassmann(arg.At(k, c)))
+			}
+			product.Set(r, c, sum)
+		}
+	}
+
+	return product, nil
+}
+
+func (m *QMatrix) Transpose() *QMatrix {
+	t := NewQMatrix(m.Cols, m.Rows)
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < m.Cols; c++ {
+			t.Set(c, r, m.At(r, c))
+		}
+	}
+
+	return t
+}
+
+// ConjugateTranspose returns the transpose with conjugated entries, for
+// which (AB)ᴴ = BᴴAᴴ.
+func (m *QMatrix) ConjugateTranspose() *QMatrix {
+	t := NewQMatrix(m.Cols, m.Rows)
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < m.Cols; c++ {
+			t.Set(c, r, m.At(r, c).Conjugate())
+		}
+	}
+
+	return t
+}
+
+// ComplexAdjoint returns the complex matrix
+//
+//	χ(Q) = [  A   B  ]    Q = A + Bj
+//	       [ -B*  A* ]
+//
+// with A and B complex in the plane of 1 and i. It maps products of
+// quaternion matrices to products of complex matrices.
+func (m *QMatrix) ComplexAdjoint() [][]complex128 {
+	chi := newComplexMatrix(2*m.Rows, 2*m.Cols)
+	for r := 0; r < m.Rows; r++ {
+		for c := 0; c < m.Cols; c++ {
+			a, b := m.At(r, c).complexPair()
+			chi[r][c], chi[r][m.Cols+c] = a, b
+			chi[m.Rows+r][c], chi[m.Rows+r][m.Cols+c] = -conj(b), conj(a)
+		}
+	}
+
+	return chi
+}
+
+// Inverse inverts the complex adjoint, whose inverse is the complex adjoint
+// of the inverse.
+func (m *QMatrix) Inverse() (*QMatrix, error) {
+	if m.Rows != m.Cols {
+		return nil, errors.Wrapf(NotSquareMatrixError, "%dx%d", m.Rows, m.Cols)
+	}
+
+	inverse, err := complexInverse(m.ComplexAdjoint())
+	if err != nil {
+		return nil, err
+	}
+
+	return fromComplexAdjoint(inverse, m.Rows, m.Cols), nil
+}
+
+// RightEigen returns the standard right eigenvalues λ = a + bi, b ≥ 0, with
+// eigenvectors as the columns of a matrix X, so that Q·xₖ = xₖ·λₖ. Every
+// eigenvalue stands for the class of its similar quaternions u⁻¹λu, which
+// are eigenvalues of the eigenvectors xₖu. They are the eigenvalues of χ(Q),
+// which come in conjugate pairs, one of each pair, sorted by descending
+// imaginary part. A repeated eigenvalue gets as many independent
+// eigenvectors as its eigenspace holds, so X is invertible whenever Q is
+// diagonalizable.
+func (m *QMatrix) RightEigen() ([]*Quaternion, *QMatrix, error) {
+	if m.Rows != m.Cols {
+		return nil, nil, errors.Wrapf(NotSquareMatrixError, "%dx%d", m.Rows, m.Cols)
+	}
+
+	n := m.Rows
+	chi := m.ComplexAdjoint()
+	lambdas := complexEigenvalues(chi)
+
+	values := make([]*Quaternion, n)
+	vectors := NewQMatrix(n, n)
+	// found holds the orthonormalized eigenvectors of χ(Q) for each
+	// eigenvalue so far, to deflate the search for a repeated one.
+	var found []struct {
+		lambda complex128
+		basis  [][]complex128
+	}
+	for k := 0; k < n; k++ {
+		// Take the eigenvalue with the largest imaginary part and drop its
+		// conjugate partner, which for a real eigenvalue is its second copy.
+		top := 0
+		for l := range lambdas {
+			if imag(lambdas[l]) > imag(lambdas[top]) {
+				top = l
+			}
+		}
+		lambda := complex(real(lambdas[top]), math.Abs(imag(lambdas[top])))
+		lambdas = append(lambdas[:top], lambdas[top+1:]...)
+		partner := 0
+		for l := range lambdas {
+			if cmplx.Abs(lambdas[l]-conj(lambda)) < cmplx.Abs(lambdas[partner]-conj(lambda)) {
+				partner = l
+			}
+		}
+		lambdas = append(lambdas[:partner], lambdas[partner+1:]...)
+
+		values[k] = NewQuaternionByCoords(real(lambda), imag(lambda), 0, 0)
+
+		class := -1
+		for l := range found {
+			if cmplx.Abs(found[l].lambda-lambda) <= repeatedEigenvalueTolerance*math.Max(1, cmplx.Abs(lambda)) {
+				class = l
+			}
+		}
+		if class < 0 {
+			found = append(found, struct {
+				lambda complex128
+				basis  [][]complex128
+			}{lambda: lambda})
+			class = len(found) - 1
+		}
+
+		// χ(Q)[u; v] = λ[u; v] gives Q(u - v*j) = (u - v*j)λ.
+		y := complexNullVector(chi, lambda, found[class].basis)
+		found[class].basis = append(found[class].basis, append([]complex128(nil), y...))
+		if imag(lambda) <= repeatedEigenvalueTolerance*math.Max(1, cmplx.Abs(lambda)) {
+			// [-v*; u*] belongs to λ* = λ and stands for the same
+			// quaternion eigenvector times j, so it is deflated as well.
+			partner := make([]complex128, 2*n)
+			for r := 0; r < n; r++ {
+				partner[r], partner[n+r] = -conj(y[n+r]), conj(y[r])
+			}
+			found[class].basis = append(found[class].basis, orthonormalize(partner, found[class].basis))
+		}
+		var norm float64
+		for r := 0; r < n; r++ {
+			q := newQuaternionFromComplexPair(y[r], -conj(y[n+r]))
+			vectors.Set(r, k, q)
+			norm += q.Norm()
+		}
+		for r := 0; r < n; r++ {
+			vectors.Set(r, k, vectors.At(r, k).MulByNumber(1/math.Sqrt(norm)))
+		}
+	}
+
+	return values, vectors, nil
+}
+
+// SVD returns U, the singular values in descending order and V with
+// Q = U·diag(σ)·Vᴴ, where U and V have orthonormal columns and the inner
+// dimension is min(Rows, Cols). It orthogonalizes the columns by one-sided
+// Jacobi rotations, each preceded by a unit quaternion factor that makes the
+// inner product of the column pair real.
+func (m *QMatrix) SVD() (*QMatrix, []float64, *QMatrix) {
+	if m.Rows < m.Cols {
+		v, sigma, u := m.ConjugateTranspose().SVD()
+		return u, sigma, v
+	}
+
+	a := NewQMatrix(m.Rows, m.Cols)
+	copy(a.Data, m.Data)
+	v := NewIdentityQMatrix(m.Cols)
+
+	for sweep := 0; sweep < jacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < m.Cols; p++ {
+			for q := p + 1; q < m.Cols; q++ {
+				alpha, beta, gamma := a.columnNorm(p), a.columnNorm(q), a.columnProduct(p, q)
+				g := math.Sqrt(gamma.Norm())
+				if g <= jacobiTolerance*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				phase := gamma.Conjugate().MulByNumber(1 / g)
+				a.mulColumn(q, phase)
+				v.mulColumn(q, phase)
+
+				zeta := (beta - alpha) / (2 * g)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Sqrt(1+zeta*zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				a.rotateColumns(p, q, c, c*t)
+				v.rotateColumns(p, q, c, c*t)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	order := make([]int, m.Cols)
+	sigma := make([]float64, m.Cols)
+	for k := range order {
+		order[k] = k
+		sigma[k] = math.Sqrt(a.columnNorm(k))
+	}
+	sort.SliceStable(order, func(x, y int) bool { return sigma[order[x]] > sigma[order[y]] })
+
+	u := NewQMatrix(m.Rows, m.Cols)
+	sorted := make([]float64, m.Cols)
+	vSorted := NewQMatrix(m.Cols, m.Cols)
+	for k, from := range order {
+		sorted[k] = sigma[from]
+		for r := 0; r < m.Cols; r++ {
+			vSorted.Set(r, k, v.At(r, from))
+		}
+		if sorted[k] > jacobiTolerance*sorted[0] {
+			for r := 0; r < m.Rows; r++ {
+				u.Set(r, k, a.At(r, from).MulByNumber(1/sorted[k]))
+			}
+			continue
+		}
+		u.completeColumn(k)
+	}
+
+	return u, sorted, vSorted
+}
+
+func (m *QMatrix) Equals(arg *QMatrix, eps float64) bool {
+	if m.Rows != arg.Rows || m.Cols != arg.Cols {
+		return false
+	}
+	for n := range m.Data {
+		if !m.Data[n].equalsByCoords(&arg.Data[n], eps) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *QMatrix) String() string {
+	rows := make([]string, m.Rows)
+	for r := range rows {
+		entries := make([]string, m.Cols)
+		for c := range entries {
+			entries[c] = m.At(r, c).String()
+		}
+		rows[r] = "[" + strings.Join(entries, " ") + "]"
+	}
+
+	return "[" + strings.Join(rows, " ") + "]"
+}
+
+// columnNorm returns the squared length of column c.
+func (m *QMatrix) columnNorm(c int) float64 {
+	var sum float64
+	for r := 0; r < m.Rows; r++ {
+		sum += m.At(r, c).Norm()
+	}
+
+	return sum
+}
+
+// columnProduct returns the inner product of columns p and q, Σ āₚaₑ.
+func (m *QMatrix) columnProduct(p, q int) *Quaternion {
+	sum := NewQuaternionByCoords(0, 0, 0, 0)
+	for r := 0; r < m.Rows; r++ {
+		sum = sum.Add(m.At(r, p).Conjugate().MulByGrassmann(m.At(r, q)))
+	}
+
+	return sum
+}
+
+// mulColumn multiplies column c by s on the right.
+func (m *QMatrix) mulColumn(c int, s *Quaternion) {
+	for r := 0; r < m.Rows; r++ {
+		m.Set(r, c, m.At(r, c).MulByGrassmann(s))
+	}
+}
+
+func (m *QMatrix) rotateColumns(p, q int, c, s float64) {
+	for r := 0; r < m.Rows; r++ {
+		x, y := m.At(r, p), m.At(r, q)
+		m.Set(r, p, x.MulByNumber(c).Sub(y.MulByNumber(s)))
+		m.Set(r, q, x.MulByNumber(s).Add(y.MulByNumber(c)))
+	}
+}
+
+// completeColumn fills column k with a unit vector orthogonal to the columns
+// before it, by Gram-Schmidt on the standard basis.
+func (m *QMatrix) completeColumn(k int) {
+	for e := 0; e < m.Rows; e++ {
+		for r := 0; r < m.Rows; r++ {
+			m.Set(r, k, NewQuaternionByCoords(0, 0, 0, 0))
+		}
+		m.Set(e, k, NewQuaternionByCoords(1, 0, 0, 0))
+
+		for c := 0; c < k; c++ {
+			projection := m.columnProduct(c, k)
+			for r := 0; r < m.Rows; r++ {
+				m.Set(r, k, m.At(r, k).Sub(m.At(r, c).MulByGrassmann(projection)))
+			}
+		}
+
+		if norm := math.Sqrt(m.columnNorm(k)); norm > 0.5 {
+			for r := 0; r < m.Rows; r++ {
+				m.Set(r, k, m.At(r, k).MulByNumber(1/norm))
+			}
+			return
+		}
+	}
+}
+
+// complexPair returns a and b with q = a + bj.
+func (q *Quaternion) complexPair() (complex128, complex128) {
+	return complex(q.W, q.I), complex(q.J, q.K)
+}
+
+func newQuaternionFromComplexPair(a, b complex128) *Quaternion {
+	return NewQuaternionByCoords(real(a), imag(a), real(b), imag(b))
+}
+
+func fromComplexAdjoint(chi [][]complex128, rows, cols int) *QMatrix {
+	m := NewQMatrix(rows, cols)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			m.Set(r, c, newQuaternionFromComplexPair(chi[r][c], chi[r][cols+c]))
+		}
+	}
+
+	return m
+}
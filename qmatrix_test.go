@@ -0,0 +1,236 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+)
+
+func randomQMatrix(rows, cols int) *QMatrix {
+	m := NewQMatrix(rows, cols)
+	for n := range m.Data {
+		m.Data[n] = *randomQuaternion()
+	}
+
+	return m
+}
+
+func mustMul(t *testing.T, a, b *QMatrix) *QMatrix {
+	product, err := a.Mul(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return product
+}
+
+func TestQMatrix_Products(t *testing.T) {
+	for n := 0; n < 20; n++ {
+		a, b, c := randomQMatrix(3, 4), randomQMatrix(4, 2), randomQMatrix(2, 5)
+
+		if got, want := mustMul(t, mustMul(t, a, b), c), mustMul(t, a, mustMul(t, b, c)); !got.Equals(want, 1e-13) {
+			t.Errorf("Wrong result of mul by associativity. Expected %v, got %v", want, got)
+		}
+		if got, want := mustMul(t, a, b).ConjugateTranspose(), mustMul(t, b.ConjugateTranspose(), a.ConjugateTranspose()); !got.Equals(want, 1e-13) {
+			t.Errorf("Wrong result of conjugate transpose of product. Expected %v, got %v", want, got)
+		}
+		if got := a.Transpose().Transpose(); !got.Equals(a, EqualsEpsilon) {
+			t.Errorf("Wrong result of double transpose. Expected %v, got %v", a, got)
+		}
+
+		// The complex adjoint is multiplicative.
+		chiA, chiB, chiAB := a.ComplexAdjoint(), b.ComplexAdjoint(), mustMul(t, a, b).ComplexAdjoint()
+		for r := range chiAB {
+			for s := range chiAB[r] {
+				var sum complex128
+				for k := range chiB {
+					sum += chiA[r][k] * chiB[k][s]
+				}
+				if cmplx.Abs(sum-chiAB[r][s]) > 1e-13 {
+					t.Errorf("Wrong complex adjoint of product at %d, %d. Expected %v, got %v", r, s, chiAB[r][s], sum)
+				}
+			}
+		}
+	}
+
+	if _, err := randomQMatrix(2, 3).Mul(randomQMatrix(2, 3)); err == nil {
+		t.Errorf("Expected error for mismatched dimensions")
+	}
+	if _, err := NewQMatrixFromRows([]*Quaternion{randomQuaternion()}, []*Quaternion{}); err == nil {
+		t.Errorf("Expected error for ragged rows")
+	}
+}
+
+func TestQMatrix_Inverse(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 6} {
+		m := randomQMatrix(n, n)
+		inverse, err := m.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := mustMul(t, m, inverse); !got.Equals(NewIdentityQMatrix(n), 1e-12) {
+			t.Errorf("Wrong result of mul by inverse for %v. Got %v", m, got)
+		}
+		if got := mustMul(t, inverse, m); !got.Equals(NewIdentityQMatrix(n), 1e-12) {
+			t.Errorf("Wrong result of mul by inverse on the left for %v. Got %v", m, got)
+		}
+	}
+
+	// The second row is the first times j on the left.
+	q := NewQuaternionByCoords(1, 2, 3, 4)
+	j := NewQuaternionByCoords(0, 0, 1, 0)
+	singular, _ := NewQMatrixFromRows(
+		[]*Quaternion{q, NewQuaternionByCoords(1, 0, 0, 0)},
+		[]*Quaternion{j.MulByGrassmann(q), j},
+	)
+	if _, err := singular.Inverse(); err == nil {
+		t.Errorf("Expected error for singular matrix")
+	}
+	if _, err := randomQMatrix(2, 3).Inverse(); err == nil {
+		t.Errorf("Expected error for non-square matrix")
+	}
+}
+
+func TestQMatrix_RightEigen(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5} {
+		m := randomQMatrix(n, n)
+		values, vectors, err := m.RightEigen()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for k, lambda := range values {
+			if lambda.I < 0 || lambda.J != 0 || lambda.K != 0 {
+				t.Errorf("Wrong standard eigenvalue %v", lambda)
+			}
+
+			x := NewQMatrix(n, 1)
+			for r := 0; r < n; r++ {
+				x.Set(r, 0, vectors.At(r, k))
+			}
+			if math.Abs(x.columnNorm(0)-1) > 1e-12 {
+				t.Errorf("Wrong eigenvector norm. Expected 1, got %v", x.columnNorm(0))
+			}
+			left := mustMul(t, m, x)
+			for r := 0; r < n; r++ {
+				if want := x.At(r, 0).MulByGrassmann(lambda); !left.At(r, 0).equalsByCoords(want, 1e-9) {
+					t.Errorf("Wrong eigenpair %v of %dx%d matrix. Expected %v, got %v", lambda, n, n, want, left.At(r, 0))
+				}
+			}
+		}
+	}
+
+	// A diagonal matrix has the standard forms of its entries as eigenvalues.
+	diagonal, _ := NewQMatrixFromRows(
+		[]*Quaternion{NewQuaternionByCoords(1, 0, 3, 4), NewQuaternionByCoords(0, 0, 0, 0)},
+		[]*Quaternion{NewQuaternionByCoords(0, 0, 0, 0), NewQuaternionByCoords(-2, 0, 0, 0)},
+	)
+	values, _, err := diagonal.RightEigen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !values[0].equalsByCoords(NewQuaternionByCoords(1, 5, 0, 0), 1e-12) || !values[1].equalsByCoords(NewQuaternionByCoords(-2, 0, 0, 0), 1e-12) {
+		t.Errorf("Wrong eigenvalues of diagonal matrix. Expected 1+5i and -2, got %v", values)
+	}
+}
+
+func TestQMatrix_RightEigen_ShouldSpanRepeatedEigenvalues(t *testing.T) {
+	j, zero := NewQuaternionByCoords(0, 0, 1, 0), NewQuaternionByCoords(0, 0, 0, 0)
+	two := NewQuaternionByCoords(2, 0, 0, 0)
+	diagonals := map[string][][]*Quaternion{
+		"diag(j, j)":    {{j, zero}, {zero, j}},
+		"diag(2, 2, j)": {{two, zero, zero}, {zero, two, zero}, {zero, zero, j}},
+	}
+	for name, rows := range diagonals {
+		diagonal, err := NewQMatrixFromRows(rows...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Conjugating by a random invertible matrix keeps the eigenvalues
+		// and hides the diagonal structure.
+		p := randomQMatrix(diagonal.Rows, diagonal.Rows)
+		pInverse, err := p.Inverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		similar := mustMul(t, mustMul(t, p, diagonal), pInverse)
+
+		for _, m := range []*QMatrix{diagonal, similar} {
+			values, vectors, err := m.RightEigen()
+			if err != nil {
+				t.Fatal(err)
+			}
+			xInverse, err := vectors.Inverse()
+			if err != nil {
+				t.Errorf("Expected invertible eigenvectors of %s, got %v", name, vectors)
+				continue
+			}
+
+			// X⁻¹QX is the diagonal of the eigenvalues.
+			want := NewQMatrix(m.Rows, m.Rows)
+			for k, lambda := range values {
+				want.Set(k, k, lambda)
+			}
+			if got := mustMul(t, mustMul(t, xInverse, m), vectors); !got.Equals(want, 1e-8) {
+				t.Errorf("Wrong diagonalization of %s. Expected %v, got %v", name, want, got)
+			}
+		}
+	}
+}
+
+func TestQMatrix_SVD(t *testing.T) {
+	rankOne := mustMul(t, randomQMatrix(4, 1), randomQMatrix(1, 3))
+
+	for _, m := range []*QMatrix{randomQMatrix(4, 3), randomQMatrix(2, 5), randomQMatrix(3, 3), rankOne} {
+		u, sigma, v := m.SVD()
+		k := m.Rows
+		if m.Cols < k {
+			k = m.Cols
+		}
+		if u.Rows != m.Rows || u.Cols != k || v.Rows != m.Cols || v.Cols != k || len(sigma) != k {
+			t.Fatalf("Wrong SVD dimensions for %dx%d. Got %dx%d, %d, %dx%d", m.Rows, m.Cols, u.Rows, u.Cols, len(sigma), v.Rows, v.Cols)
+		}
+
+		if !sort.SliceIsSorted(sigma, func(a, b int) bool { return sigma[a] > sigma[b] }) {
+			t.Errorf("Wrong order of singular values %v", sigma)
+		}
+		if got := mustMul(t, u.ConjugateTranspose(), u); !got.Equals(NewIdentityQMatrix(k), 1e-12) {
+			t.Errorf("Wrong U, UᴴU is %v", got)
+		}
+		if got := mustMul(t, v.ConjugateTranspose(), v); !got.Equals(NewIdentityQMatrix(k), 1e-12) {
+			t.Errorf("Wrong V, VᴴV is %v", got)
+		}
+
+		s := NewQMatrix(k, k)
+		var frobenius, squares float64
+		for n := 0; n < k; n++ {
+			s.Set(n, n, NewQuaternionByCoords(sigma[n], 0, 0, 0))
+			squares += sigma[n] * sigma[n]
+		}
+		for _, q := range m.Data {
+			frobenius += q.Norm()
+		}
+		if got := mustMul(t, mustMul(t, u, s), v.ConjugateTranspose()); !got.Equals(m, 1e-12) {
+			t.Errorf("Wrong SVD of %v. Product is %v", m, got)
+		}
+		if math.Abs(frobenius-squares) > 1e-12*frobenius {
+			t.Errorf("Wrong singular values %v. Expected squares summing to %v", sigma, frobenius)
+		}
+	}
+
+	// The eigenvalues of the Hermitian matrix MᴴM are the squared singular
+	// values.
+	m := randomQMatrix(3, 3)
+	_, sigma, _ := m.SVD()
+	values, _, err := mustMul(t, m.ConjugateTranspose(), m).RightEigen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(values, func(a, b int) bool { return values[a].W > values[b].W })
+	for n, lambda := range values {
+		if math.Abs(lambda.W-sigma[n]*sigma[n]) > 1e-10 || math.Abs(lambda.I) > 1e-6 {
+			t.Errorf("Wrong eigenvalue %v of MᴴM. Expected %v", lambda, sigma[n]*sigma[n])
+		}
+	}
+}
@@ -114,6 +114,26 @@ func (q *Quaternion) ToRotateQuaternion(angle float64) (*Quaternion, error) {
 	return normQ, nil
 }
 
+// ToAxisAngle is the inverse of ToRotateQuaternion. The angle is in [0, 2π];
+// for the identity rotation the axis is X.
+func (q *Quaternion) ToAxisAngle() (*Vec3, float64, error) {
+	normQ, err := q.Normalize()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sin := math.Sqrt(normQ.I*normQ.I + normQ.J*normQ.J + normQ.K*normQ.K)
+	if sin == 0 {
+		return &Vec3{X: 1}, 0, nil
+	}
+
+	return &Vec3{
+		X: normQ.I / sin,
+		Y: normQ.J / sin,
+		Z: normQ.K / sin,
+	}, 2 * math.Atan2(sin, normQ.W), nil
+}
+
 func (q *Quaternion) String() string {
 	return fmt.Sprintf("(%v)+(%v)i+(%v)j+(%v)k", q.W, q.I, q.J, q.K)
 }
@@ -0,0 +1,134 @@
+package go_quaternions
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"math"
+	"math/big"
+)
+
+var NotFiniteError = errors.WithStack(errors.New("Component is not finite"))
+
+// RatQuaternion is a quaternion with exact rational components. Every
+// float64 converts to it exactly, so identities that hold only up to
+// rounding for Quaternion can be checked with plain equality.
+type RatQuaternion struct {
+	W, I, J, K *big.Rat
+}
+
+// NewRatQuaternionByCoords copies the components.
+func NewRatQuaternionByCoords(W, I, J, K *big.Rat) *RatQuaternion {
+	return &RatQuaternion{
+		W: new(big.Rat).Set(W),
+		I: new(big.Rat).Set(I),
+		J: new(big.Rat).Set(J),
+		K: new(big.Rat).Set(K),
+	}
+}
+
+func NewRatQuaternionFromFloat(q *Quaternion) (*RatQuaternion, error) {
+	c := [4]*big.Rat{}
+	for n, f := range [4]float64{q.W, q.I, q.J, q.K} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, errors.Wrapf(NotFiniteError, "%v", q)
+		}
+		c[n] = new(big.Rat).SetFloat64(f)
+	}
+
+	return &RatQuaternion{W: c[0], I: c[1], J: c[2], K: c[3]}, nil
+}
+
+// Float returns the nearest Quaternion.
+func (q *RatQuaternion) Float() *Quaternion {
+	w, _ := q.W.Float64()
+	i, _ := q.I.Float64()
+	j, _ := q.J.Float64()
+	k, _ := q.K.Float64()
+
+	return NewQuaternionByCoords(w, i, j, k)
+}
+
+func (q *RatQuaternion) Add(arg *RatQuaternion) *RatQuaternion {
+	return &RatQuaternion{
+		W: new(big.Rat).Add(q.W, arg.W),
+		I: new(big.Rat).Add(q.I, arg.I),
+		J: new(big.Rat).Add(q.J, arg.J),
+		K: new(big.Rat).Add(q.K, arg.K),
+	}
+}
+
+func (q *RatQuaternion) Sub(arg *RatQuaternion) *RatQuaternion {
+	return &RatQuaternion{
+		W: new(big.Rat).Sub(q.W, arg.W),
+		I: new(big.Rat).Sub(q.I, arg.I),
+		J: new(big.Rat).Sub(q.J, arg.J),
+		K: new(big.Rat).Sub(q.K, arg.K),
+	}
+}
+
+func (q *RatQuaternion) MulByGrassmann(arg *RatQuaternion) *RatQuaternion {
+	return &RatQuaternion{
+		W: ratSum(ratMul(q.W, arg.W), ratNeg(ratMul(q.I, arg.I)), ratNeg(ratMul(q.J, arg.J)), ratNeg(ratMul(q.K, arg.K))),
+		I: ratSum(ratMul(q.W, arg.I), ratMul(q.I, arg.W), ratMul(q.J, arg.K), ratNeg(ratMul(q.K, arg.J))),
+		J: ratSum(ratMul(q.W, arg.J), ratNeg(ratMul(q.I, arg.K)), ratMul(q.J, arg.W), ratMul(q.K, arg.I)),
+		K: ratSum(ratMul(q.W, arg.K), ratMul(q.I, arg.J), ratNeg(ratMul(q.J, arg.I)), ratMul(q.K, arg.W)),
+	}
+}
+
+func (q *RatQuaternion) MulByNumber(n *big.Rat) *RatQuaternion {
+	return &RatQuaternion{
+		W: ratMul(q.W, n),
+		I: ratMul(q.I, n),
+		J: ratMul(q.J, n),
+		K: ratMul(q.K, n),
+	}
+}
+
+func (q *RatQuaternion) Conjugate() *RatQuaternion {
+	return &RatQuaternion{
+		W: new(big.Rat).Set(q.W),
+		I: new(big.Rat).Neg(q.I),
+		J: new(big.Rat).Neg(q.J),
+		K: new(big.Rat).Neg(q.K),
+	}
+}
+
+// Norm returns the squared length, as Quaternion.Norm does.
+func (q *RatQuaternion) Norm() *big.Rat {
+	return ratSum(ratMul(q.W, q.W), ratMul(q.I, q.I), ratMul(q.J, q.J), ratMul(q.K, q.K))
+}
+
+func (q *RatQuaternion) Reverse() (*RatQuaternion, error) {
+	norm := q.Norm()
+	if norm.Sign() == 0 {
+		return nil, AllComponentsEqualsToZeroError
+	}
+
+	return q.Conjugate().MulByNumber(norm.Inv(norm)), nil
+}
+
+// Equals reports exact equality.
+func (q *RatQuaternion) Equals(arg *RatQuaternion) bool {
+	return q.W.Cmp(arg.W) == 0 && q.I.Cmp(arg.I) == 0 && q.J.Cmp(arg.J) == 0 && q.K.Cmp(arg.K) == 0
+}
+
+func (q *RatQuaternion) String() string {
+	return fmt.Sprintf("(%v)+(%v)i+(%v)j+(%v)k", q.W.RatString(), q.I.RatString(), q.J.RatString(), q.K.RatString())
+}
+
+func ratMul(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
+
+func ratNeg(a *big.Rat) *big.Rat {
+	return a.Neg(a)
+}
+
+func ratSum(values ...*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for _, v := range values {
+		sum.Add(sum, v)
+	}
+
+	return sum
+}
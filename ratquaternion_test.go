@@ -0,0 +1,88 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func randomRatQuaternion(t *testing.T) *RatQuaternion {
+	q, err := NewRatQuaternionFromFloat(NewQuaternionByCoords(rand.Float64()*2-1, rand.Float64()*2-1, rand.Float64()*2-1, rand.Float64()*2-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return q
+}
+
+func TestRatQuaternion_ShouldSatisfyIdentitiesExactly(t *testing.T) {
+	one := NewRatQuaternionByCoords(big.NewRat(1, 1), new(big.Rat), new(big.Rat), new(big.Rat))
+
+	for n := 0; n < 100; n++ {
+		p, q, r := randomRatQuaternion(t), randomRatQuaternion(t), randomRatQuaternion(t)
+
+		if !p.MulByGrassmann(q).MulByGrassmann(r).Equals(p.MulByGrassmann(q.MulByGrassmann(r))) {
+			t.Errorf("Wrong result of mul for %v, %v and %v by associativity", p, q, r)
+		}
+		if !p.MulByGrassmann(q.Add(r)).Equals(p.MulByGrassmann(q).Add(p.MulByGrassmann(r))) {
+			t.Errorf("Wrong result of mul for %v, %v and %v by distributivity", p, q, r)
+		}
+		if !p.MulByGrassmann(q).Conjugate().Equals(q.Conjugate().MulByGrassmann(p.Conjugate())) {
+			t.Errorf("Wrong result of mul for %v and %v by conjugate", p, q)
+		}
+		if p.MulByGrassmann(q).Norm().Cmp(ratMul(p.Norm(), q.Norm())) != 0 {
+			t.Errorf("Wrong norm of product of %v and %v", p, q)
+		}
+
+		reverse, err := p.Reverse()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.MulByGrassmann(reverse).Equals(one) || !reverse.MulByGrassmann(p).Equals(one) {
+			t.Errorf("Wrong result of mul for %v and its reverse %v", p, reverse)
+		}
+		if !p.Add(q).Sub(q).Equals(p) {
+			t.Errorf("Wrong result of add and sub for %v and %v", p, q)
+		}
+	}
+}
+
+func TestRatQuaternion_ShouldMatchQuaternion(t *testing.T) {
+	a := NewQuaternionByCoords(rand.Float64(), -rand.Float64(), rand.Float64(), 3)
+	b := NewQuaternionByCoords(-2, rand.Float64(), 0.5, -rand.Float64())
+	ra, _ := NewRatQuaternionFromFloat(a)
+	rb, _ := NewRatQuaternionFromFloat(b)
+
+	if got := ra.Float(); *got != *a {
+		t.Errorf("Wrong result of float round trip. Expected %v, got %v", a, got)
+	}
+	if got, want := ra.MulByGrassmann(rb).Float(), a.MulByGrassmann(b); !got.equalsByCoords(want, 1e-14) {
+		t.Errorf("Wrong result of mul. Expected %v, got %v", want, got)
+	}
+	if got, want := ra.MulByNumber(big.NewRat(1, 3)).Float(), a.MulByNumber(1.0/3); !got.equalsByCoords(want, 1e-15) {
+		t.Errorf("Wrong result of mul by number. Expected %v, got %v", want, got)
+	}
+	if got, _ := ra.Norm().Float64(); math.Abs(got-a.Norm()) > 1e-14 {
+		t.Errorf("Wrong norm. Expected %v, got %v", a.Norm(), got)
+	}
+}
+
+func TestRatQuaternion_ShouldFailForInvalidValues(t *testing.T) {
+	if _, err := NewRatQuaternionFromFloat(NewQuaternionByCoords(1, math.NaN(), 0, 0)); err == nil {
+		t.Errorf("Expected error for NaN")
+	}
+	if _, err := NewRatQuaternionFromFloat(NewQuaternionByCoords(math.Inf(-1), 0, 0, 0)); err == nil {
+		t.Errorf("Expected error for infinity")
+	}
+
+	zero, _ := NewRatQuaternionFromFloat(NewQuaternionByCoords(0, 0, 0, 0))
+	if _, err := zero.Reverse(); err == nil {
+		t.Errorf("Expected error for reverse of zero")
+	}
+
+	q := NewRatQuaternionByCoords(big.NewRat(1, 2), big.NewRat(-1, 3), new(big.Rat), big.NewRat(4, 1))
+	if got := q.String(); got != "(1/2)+(-1/3)i+(0)j+(4)k" {
+		t.Errorf("Wrong result of String. Expected (1/2)+(-1/3)i+(0)j+(4)k, got %v", got)
+	}
+}
@@ -0,0 +1,210 @@
+// Package scene provides a hierarchy of nodes with local transforms and
+// lazily computed world transforms.
+//
+// A node maps a point x in its own frame to s*R*x + t in its parent frame,
+// where R is a unit rotation Quaternion, t a translation and s a uniform
+// scale. World transforms are cached and recomputed only after the node or
+// one of its ancestors changed.
+package scene
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+)
+
+var (
+	CycleError     = errors.WithStack(errors.New("Node cannot be attached to its own descendant"))
+	ZeroScaleError = errors.WithStack(errors.New("Scale must not be zero"))
+)
+
+type Node struct {
+	Name string
+
+	rotation    *quat.Quaternion
+	translation *quat.Vec3
+	scale       float64
+
+	parent   *Node
+	children []*Node
+
+	dirty            bool
+	worldRotation    *quat.Quaternion
+	worldTranslation *quat.Vec3
+	worldScale       float64
+}
+
+func NewNode(name string) *Node {
+	return &Node{
+		Name:        name,
+		rotation:    quat.NewQuaternionByCoords(1, 0, 0, 0),
+		translation: &quat.Vec3{},
+		scale:       1,
+		dirty:       true,
+	}
+}
+
+// Rotation returns a copy of the local rotation; changes go through
+// SetRotation so that the world transforms below n are invalidated.
+func (n *Node) Rotation() *quat.Quaternion {
+	return copyRotation(n.rotation)
+}
+
+// Translation returns a copy of the local translation.
+func (n *Node) Translation() *quat.Vec3 {
+	return copyVec3(n.translation)
+}
+
+func (n *Node) Scale() float64 {
+	return n.scale
+}
+
+// SetRotation and SetTranslation store copies of their arguments.
+func (n *Node) SetRotation(rotation *quat.Quaternion) {
+	n.rotation = copyRotation(rotation)
+	n.markDirty()
+}
+
+func (n *Node) SetTranslation(translation *quat.Vec3) {
+	n.translation = copyVec3(translation)
+	n.markDirty()
+}
+
+func (n *Node) SetScale(scale float64) error {
+	if scale == 0 {
+		return ZeroScaleError
+	}
+	n.scale = scale
+	n.markDirty()
+
+	return nil
+}
+
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// AddChild attaches child below n keeping its local transform.
+func (n *Node) AddChild(child *Node) error {
+	return child.SetParent(n, false)
+}
+
+// SetParent moves n below parent, or makes it a root when parent is nil.
+// With keepWorld the local transform is recomputed so that the world pose
+// of n does not change.
+func (n *Node) SetParent(parent *Node, keepWorld bool) error {
+	for p := parent; p != nil; p = p.parent {
+		if p == n {
+			return CycleError
+		}
+	}
+
+	if keepWorld {
+		rotation, translation, scale := n.World()
+		if parent != nil {
+			pr, pt, ps := parent.World()
+			inv := pr.Conjugate()
+			rotation = inv.MulByGrassmann(rotation)
+			translation = scaled(inv.Compile().Apply(translation.Sub(pt)), 1/ps)
+			scale /= ps
+		}
+		n.rotation, n.translation, n.scale = rotation, translation, scale
+	}
+
+	if n.parent != nil {
+		siblings := n.parent.children
+		for k, c := range siblings {
+			if c == n {
+				n.parent.children = append(siblings[:k:k], siblings[k+1:]...)
+				break
+			}
+		}
+	}
+	n.parent = parent
+	if parent != nil {
+		parent.children = append(parent.children, n)
+	}
+	n.markDirty()
+
+	return nil
+}
+
+// World returns the rotation, translation and scale mapping points in the
+// frame of n to the root frame. The rotation and translation are copies of
+// the cache.
+func (n *Node) World() (*quat.Quaternion, *quat.Vec3, float64) {
+	n.update()
+
+	return copyRotation(n.worldRotation), copyVec3(n.worldTranslation), n.worldScale
+}
+
+// WorldTransform compiles the world transform, scale included, for applying
+// to many points.
+func (n *Node) WorldTransform() *quat.Transform {
+	rotation, translation, scale := n.World()
+
+	op := rotation.Compile()
+	for r := range op.R {
+		for c := range op.R[r] {
+			op.R[r][c] *= scale
+		}
+	}
+	op.T = *translation
+
+	return op
+}
+
+func (n *Node) LocalToWorld(point *quat.Vec3) *quat.Vec3 {
+	return n.WorldTransform().Apply(point)
+}
+
+func (n *Node) WorldToLocal(point *quat.Vec3) *quat.Vec3 {
+	rotation, translation, scale := n.World()
+
+	return scaled(rotation.Conjugate().Compile().Apply(point.Sub(translation)), 1/scale)
+}
+
+// markDirty invalidates the cached world transform of n and its subtree. A
+// dirty node always has dirty descendants, so the walk stops early.
+func (n *Node) markDirty() {
+	if n.dirty {
+		return
+	}
+	n.dirty = true
+	for _, c := range n.children {
+		c.markDirty()
+	}
+}
+
+func (n *Node) update() {
+	if !n.dirty {
+		return
+	}
+
+	if n.parent == nil {
+		n.worldRotation, n.worldTranslation, n.worldScale = n.rotation, n.translation, n.scale
+	} else {
+		pr, pt, ps := n.parent.World()
+		t := scaled(pr.Compile().Apply(n.translation), ps)
+
+		n.worldRotation = pr.MulByGrassmann(n.rotation)
+		n.worldTranslation = &quat.Vec3{X: pt.X + t.X, Y: pt.Y + t.Y, Z: pt.Z + t.Z}
+		n.worldScale = ps * n.scale
+	}
+	n.dirty = false
+}
+
+func copyRotation(q *quat.Quaternion) *quat.Quaternion {
+	return quat.NewQuaternionByCoords(q.W, q.I, q.J, q.K)
+}
+
+func copyVec3(v *quat.Vec3) *quat.Vec3 {
+	return &quat.Vec3{X: v.X, Y: v.Y, Z: v.Z}
+}
+
+func scaled(v *quat.Vec3, s float64) *quat.Vec3 {
+	return &quat.Vec3{X: s * v.X, Y: s * v.Y, Z: s * v.Z}
+}
@@ -0,0 +1,150 @@
+package scene
+
+import (
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func rotationZ(angle float64) *quat.Quaternion {
+	return quat.NewQuaternionByCoords(math.Cos(angle/2), 0, 0, math.Sin(angle/2))
+}
+
+func randomRotation() *quat.Quaternion {
+	q, _ := quat.NewQuaternionByCoords(rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64()).Normalize()
+	return q
+}
+
+// arm builds base -> shoulder -> elbow -> tool, each link one unit along X
+// and turned a quarter turn around Z.
+func arm(t *testing.T) []*Node {
+	nodes := []*Node{NewNode("base"), NewNode("shoulder"), NewNode("elbow"), NewNode("tool")}
+	for k := 1; k < len(nodes); k++ {
+		nodes[k].SetRotation(rotationZ(math.Pi / 2))
+		nodes[k].SetTranslation(&quat.Vec3{X: 1})
+		if err := nodes[k-1].AddChild(nodes[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return nodes
+}
+
+func TestNode_World(t *testing.T) {
+	nodes := arm(t)
+	tool := nodes[3]
+
+	if got, want := tool.LocalToWorld(&quat.Vec3{}), (&quat.Vec3{Y: 1}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong tool position. Expected %v, got %v", want, got)
+	}
+	if got, want := tool.LocalToWorld(&quat.Vec3{X: 1}), (&quat.Vec3{}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong tool X axis. Expected %v, got %v", want, got)
+	}
+
+	if err := nodes[1].SetScale(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tool.LocalToWorld(&quat.Vec3{}), (&quat.Vec3{X: -1, Y: 2}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong tool position after scaling. Expected %v, got %v", want, got)
+	}
+
+	p := &quat.Vec3{X: 0.3, Y: -2, Z: 5}
+	if got := tool.WorldToLocal(tool.LocalToWorld(p)); !got.Equals(p, 1e-12) {
+		t.Errorf("Wrong round trip of %v, got %v", p, got)
+	}
+}
+
+func TestNode_ShouldRecomputeOnlyDirtySubtree(t *testing.T) {
+	nodes := arm(t)
+	sensor := NewNode("sensor")
+	if err := nodes[0].AddChild(sensor); err != nil {
+		t.Fatal(err)
+	}
+	nodes[3].World()
+	sensor.World()
+
+	for _, n := range append(nodes, sensor) {
+		if n.dirty {
+			t.Fatalf("Expected %v to be cached", n.Name)
+		}
+	}
+
+	nodes[2].SetTranslation(&quat.Vec3{X: 3})
+	for _, n := range append(nodes, sensor) {
+		if want := n == nodes[2] || n == nodes[3]; n.dirty != want {
+			t.Errorf("Wrong dirty flag of %v. Expected %v", n.Name, want)
+		}
+	}
+
+	if got, want := nodes[3].LocalToWorld(&quat.Vec3{}), (&quat.Vec3{Y: 3}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong tool position after moving elbow. Expected %v, got %v", want, got)
+	}
+}
+
+func TestNode_Getters_ShouldNotExposeState(t *testing.T) {
+	nodes := arm(t)
+	base, tool := nodes[0], nodes[3]
+	want := tool.LocalToWorld(&quat.Vec3{})
+
+	// Writes through returned pointers must not reach the cached transforms.
+	*base.Rotation() = *rotationZ(1)
+	*base.Translation() = quat.Vec3{X: 5}
+	rotation, translation, _ := base.World()
+	*rotation = *rotationZ(1)
+	*translation = quat.Vec3{X: 5}
+	r, _, _ := tool.World()
+	r.W = 0
+
+	if got := tool.LocalToWorld(&quat.Vec3{}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong tool position after writing through getters. Expected %v, got %v", want, got)
+	}
+}
+
+func TestNode_SetParent_ShouldKeepWorldPose(t *testing.T) {
+	nodes := arm(t)
+	other := NewNode("table")
+	other.SetRotation(randomRotation())
+	other.SetTranslation(&quat.Vec3{X: rand.NormFloat64(), Y: rand.NormFloat64(), Z: rand.NormFloat64()})
+	if err := other.SetScale(0.5); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := nodes[3]
+	points := []*quat.Vec3{{}, {X: 1}, {Y: 1}, {Z: 1}}
+	before := make([]*quat.Vec3, len(points))
+	for k, p := range points {
+		before[k] = tool.LocalToWorld(p)
+	}
+
+	if err := tool.SetParent(other, true); err != nil {
+		t.Fatal(err)
+	}
+	if tool.Parent() != other || len(nodes[2].Children()) != 0 || len(other.Children()) != 1 {
+		t.Fatalf("Wrong hierarchy after reparenting")
+	}
+
+	for k, p := range points {
+		if got := tool.LocalToWorld(p); !got.Equals(before[k], 1e-12) {
+			t.Errorf("World position of %v changed from %v to %v", p, before[k], got)
+		}
+	}
+
+	if err := tool.SetParent(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := tool.LocalToWorld(&quat.Vec3{}); !got.Equals(before[0], 1e-12) {
+		t.Errorf("World position changed from %v to %v after detaching", before[0], got)
+	}
+}
+
+func TestNode_SetParent_ShouldRejectCycles(t *testing.T) {
+	nodes := arm(t)
+
+	if err := nodes[1].SetParent(nodes[3], false); err != CycleError {
+		t.Errorf("Expected CycleError, got %v", err)
+	}
+	if err := nodes[1].SetParent(nodes[1], false); err != CycleError {
+		t.Errorf("Expected CycleError, got %v", err)
+	}
+}
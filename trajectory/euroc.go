@@ -0,0 +1,70 @@
+package trajectory
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const eurocHeader = "#timestamp [ns],p_RS_R_x [m],p_RS_R_y [m],p_RS_R_z [m],q_RS_w [],q_RS_x [],q_RS_y [],q_RS_z []"
+
+// ReadEuRoC reads the ground truth CSV of the EuRoC MAV dataset. Columns past
+// the orientation (velocities and biases) are ignored. The nanosecond
+// timestamps are kept in Nanoseconds and converted to seconds in Time.
+func ReadEuRoC(r io.Reader) ([]StampedPose, error) {
+	var poses []StampedPose
+
+	err := readLines(r, func(fields []string) error {
+		if len(fields) < 8 {
+			return errors.Wrapf(MalformedLineError, "expected at least 8 values, got %d", len(fields))
+		}
+		ns, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return errors.Wrap(MalformedLineError, err.Error())
+		}
+
+		v, err := parseFloats(fields[1:8], 7)
+		if err != nil {
+			return err
+		}
+
+		pose, err := newPose(quat.NewQuaternionByCoords(v[3], v[4], v[5], v[6]), &quat.Vec3{X: v[0], Y: v[1], Z: v[2]})
+		if err != nil {
+			return err
+		}
+		poses = append(poses, StampedPose{Time: float64(ns) * 1e-9, Nanoseconds: ns, Pose: pose})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return poses, nil
+}
+
+// WriteEuRoC writes Nanoseconds as the timestamp, or Time rounded to
+// nanoseconds when Nanoseconds is zero.
+func WriteEuRoC(w io.Writer, poses []StampedPose) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(eurocHeader + "\n"); err != nil {
+		return err
+	}
+
+	for _, p := range poses {
+		t, q := p.Pose.Translation(), p.Pose.P
+
+		fields := []string{strconv.FormatInt(p.nanoseconds(), 10)}
+		for _, v := range []float64{t.X, t.Y, t.Z, q.W, q.I, q.J, q.K} {
+			fields = append(fields, formatFloat(v))
+		}
+		if _, err := bw.WriteString(strings.Join(fields, ",") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
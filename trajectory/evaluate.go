@@ -0,0 +1,306 @@
+package trajectory
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"math"
+	"sort"
+)
+
+var (
+	NotEnoughPosesError      = errors.WithStack(errors.New("Not enough associated poses"))
+	DegenerateAlignmentError = errors.WithStack(errors.New("Positions are degenerate for alignment"))
+	SegmentLengthError       = errors.WithStack(errors.New("Segment length must be positive"))
+)
+
+type Statistics struct {
+	RMSE, Mean, Median, Max float64
+	Count                   int
+}
+
+// Errors holds translation errors in trajectory units and rotation errors in
+// radians.
+type Errors struct {
+	Translation Statistics
+	Rotation    Statistics
+}
+
+type ATEOptions struct {
+	// MaxTimeDifference is the largest timestamp gap, in seconds, for which an
+	// estimated pose is matched to a ground truth pose.
+	MaxTimeDifference float64
+	// Scale also estimates a scale factor, as needed for monocular odometry.
+	Scale bool
+}
+
+type RPEOptions struct {
+	MaxTimeDifference float64
+	// Lengths are the segment lengths measured as distance traveled along the
+	// ground truth, as in the KITTI odometry benchmark. They must be positive.
+	Lengths []float64
+}
+
+type SegmentErrors struct {
+	Length float64
+	Errors
+}
+
+// Associate pairs every estimated pose with the ground truth pose nearest in
+// time, dropping pairs further apart than maxDifference. Both inputs must be
+// sorted by time. The matching is not one-to-one: when the estimate is
+// sampled faster than the ground truth, one ground truth pose may be paired
+// with several estimated poses.
+func Associate(estimate, truth []StampedPose, maxDifference float64) ([]StampedPose, []StampedPose) {
+	var est, gt []StampedPose
+
+	k := 0
+	for _, e := range estimate {
+		for k+1 < len(truth) && math.Abs(truth[k+1].Time-e.Time) <= math.Abs(truth[k].Time-e.Time) {
+			k++
+		}
+		if k < len(truth) && math.Abs(truth[k].Time-e.Time) <= maxDifference {
+			est = append(est, e)
+			gt = append(gt, truth[k])
+		}
+	}
+
+	return est, gt
+}
+
+// Align finds the similarity transform x ↦ scale*R*x + t that best maps the
+// estimated positions onto the ground truth ones in the least squares sense,
+// using Horn's closed form quaternion solution. Without withScale the scale
+// is 1.
+func Align(estimate, truth []StampedPose, withScale bool) (*quat.BQuaternion, float64, error) {
+	if len(estimate) != len(truth) || len(estimate) < 3 {
+		return nil, 0, NotEnoughPosesError
+	}
+
+	est, gt := positions(estimate), positions(truth)
+	me, mg := centroid(est), centroid(gt)
+
+	var s [3][3]float64
+	var variance float64
+	for k := range est {
+		a := [3]float64{est[k].X - me.X, est[k].Y - me.Y, est[k].Z - me.Z}
+		b := [3]float64{gt[k].X - mg.X, gt[k].Y - mg.Y, gt[k].Z - mg.Z}
+		for r := range a {
+			for c := range b {
+				s[r][c] += a[r] * b[c]
+			}
+			variance += a[r] * a[r]
+		}
+	}
+	if variance == 0 {
+		return nil, 0, DegenerateAlignmentError
+	}
+
+	n := [4][4]float64{
+		{s[0][0] + s[1][1] + s[2][2], s[1][2] - s[2][1], s[2][0] - s[0][2], s[0][1] - s[1][0]},
+		{s[1][2] - s[2][1], s[0][0] - s[1][1] - s[2][2], s[0][1] + s[1][0], s[2][0] + s[0][2]},
+		{s[2][0] - s[0][2], s[0][1] + s[1][0], -s[0][0] + s[1][1] - s[2][2], s[1][2] + s[2][1]},
+		{s[0][1] - s[1][0], s[2][0] + s[0][2], s[1][2] + s[2][1], -s[0][0] - s[1][1] + s[2][2]},
+	}
+	values, vectors := symmetricEigen4(n)
+
+	best := 0
+	for k := range values {
+		if values[k] > values[best] {
+			best = k
+		}
+	}
+	rotation, err := quat.NewQuaternionByCoords(vectors[0][best], vectors[1][best], vectors[2][best], vectors[3][best]).Normalize()
+	if err != nil {
+		return nil, 0, DegenerateAlignmentError
+	}
+
+	scale := 1.0
+	if withScale {
+		// The maximal eigenvalue equals the sum of gt·R*est over all points.
+		scale = values[best] / variance
+	}
+
+	rotated := rotation.Compile().Apply(me)
+	t := &quat.Vec3{X: mg.X - scale*rotated.X, Y: mg.Y - scale*rotated.Y, Z: mg.Z - scale*rotated.Z}
+
+	return quat.NewRigidBQuaternion(rotation, t), scale, nil
+}
+
+// ATE computes the absolute trajectory error after aligning the estimate to
+// the ground truth.
+func ATE(estimate, truth []StampedPose, opts ATEOptions) (*Errors, error) {
+	est, gt := Associate(estimate, truth, opts.MaxTimeDifference)
+
+	alignment, scale, err := Align(est, gt, opts.Scale)
+	if err != nil {
+		return nil, err
+	}
+	op := alignment.Compile()
+
+	translation := make([]float64, len(est))
+	rotation := make([]float64, len(est))
+	for k := range est {
+		p := est[k].Pose.Translation()
+		p = op.Apply(&quat.Vec3{X: scale * p.X, Y: scale * p.Y, Z: scale * p.Z})
+		translation[k] = p.Sub(gt[k].Pose.Translation()).Length()
+
+		if rotation[k], err = alignment.P.MulByGrassmann(est[k].Pose.P).AngularDistance(gt[k].Pose.P); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Errors{
+		Translation: newStatistics(translation),
+		Rotation:    newStatistics(rotation),
+	}, nil
+}
+
+// RPE computes the relative pose error over segments of every requested
+// length. Relative errors do not depend on a global alignment, so none is
+// applied.
+func RPE(estimate, truth []StampedPose, opts RPEOptions) ([]SegmentErrors, error) {
+	for _, length := range opts.Lengths {
+		if !(length > 0) {
+			return nil, errors.Wrapf(SegmentLengthError, "length %v", length)
+		}
+	}
+
+	est, gt := Associate(estimate, truth, opts.MaxTimeDifference)
+	if len(est) < 2 {
+		return nil, NotEnoughPosesError
+	}
+
+	traveled := make([]float64, len(gt))
+	for k := 1; k < len(gt); k++ {
+		traveled[k] = traveled[k-1] + gt[k].Pose.Translation().Sub(gt[k-1].Pose.Translation()).Length()
+	}
+
+	res := make([]SegmentErrors, 0, len(opts.Lengths))
+	for _, length := range opts.Lengths {
+		var translation, rotation []float64
+
+		j := 0
+		for i := range gt {
+			for j < len(gt) && traveled[j]-traveled[i] < length {
+				j++
+			}
+			if j == len(gt) {
+				break
+			}
+
+			dGT := gt[i].Pose.ComplexConjugate().Mul(gt[j].Pose)
+			dEst := est[i].Pose.ComplexConjugate().Mul(est[j].Pose)
+			e := dGT.ComplexConjugate().Mul(dEst)
+
+			angle, err := e.P.AngularDistance(quat.NewQuaternionByCoords(1, 0, 0, 0))
+			if err != nil {
+				return nil, err
+			}
+			translation = append(translation, e.Translation().Length())
+			rotation = append(rotation, angle)
+		}
+
+		res = append(res, SegmentErrors{
+			Length: length,
+			Errors: Errors{
+				Translation: newStatistics(translation),
+				Rotation:    newStatistics(rotation),
+			},
+		})
+	}
+
+	return res, nil
+}
+
+func newStatistics(values []float64) Statistics {
+	s := Statistics{Count: len(values)}
+	if len(values) == 0 {
+		return s
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum, squares float64
+	for _, v := range sorted {
+		sum += v
+		squares += v * v
+	}
+
+	s.Mean = sum / float64(len(sorted))
+	s.RMSE = math.Sqrt(squares / float64(len(sorted)))
+	s.Max = sorted[len(sorted)-1]
+	if m := len(sorted) / 2; len(sorted)%2 == 1 {
+		s.Median = sorted[m]
+	} else {
+		s.Median = (sorted[m-1] + sorted[m]) / 2
+	}
+
+	return s
+}
+
+func positions(poses []StampedPose) []*quat.Vec3 {
+	res := make([]*quat.Vec3, len(poses))
+	for k, p := range poses {
+		res[k] = p.Pose.Translation()
+	}
+
+	return res
+}
+
+func centroid(points []*quat.Vec3) *quat.Vec3 {
+	c := &quat.Vec3{}
+	for _, p := range points {
+		c.X += p.X / float64(len(points))
+		c.Y += p.Y / float64(len(points))
+		c.Z += p.Z / float64(len(points))
+	}
+
+	return c
+}
+
+// symmetricEigen4 diagonalizes a symmetric matrix with cyclic Jacobi
+// rotations. Column k of the returned matrix is the eigenvector of value k.
+func symmetricEigen4(a [4][4]float64) ([4]float64, [4][4]float64) {
+	v := [4][4]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		var off float64
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < 4; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < 4; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < 4; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	return [4]float64{a[0][0], a[1][1], a[2][2], a[3][3]}, v
+}
@@ -0,0 +1,156 @@
+package trajectory
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomPose() *quat.BQuaternion {
+	r, _ := quat.NewQuaternionByCoords(rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64(), rand.NormFloat64()).Normalize()
+
+	return quat.NewRigidBQuaternion(r, &quat.Vec3{X: 10 * rand.NormFloat64(), Y: 10 * rand.NormFloat64(), Z: rand.NormFloat64()})
+}
+
+func randomTrajectory(n int) []StampedPose {
+	poses := make([]StampedPose, n)
+	for k := range poses {
+		poses[k] = StampedPose{Time: float64(k) * 0.1, Pose: randomPose()}
+	}
+
+	return poses
+}
+
+// transformed maps every pose p to x ↦ scale*R*p(x) + t.
+func transformed(poses []StampedPose, by *quat.BQuaternion, scale float64) []StampedPose {
+	op := by.Compile()
+
+	res := make([]StampedPose, len(poses))
+	for k, p := range poses {
+		t := p.Pose.Translation()
+		t = op.Apply(&quat.Vec3{X: scale * t.X, Y: scale * t.Y, Z: scale * t.Z})
+		res[k] = StampedPose{Time: p.Time, Pose: quat.NewRigidBQuaternion(by.P.MulByGrassmann(p.Pose.P), t)}
+	}
+
+	return res
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name      string
+		scale     float64
+		withScale bool
+	}{
+		{name: "rigid", scale: 1},
+		{name: "similarity", scale: 2.5, withScale: true},
+		{name: "shrinking similarity", scale: 0.1, withScale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			truth := randomTrajectory(30)
+			by := randomPose()
+			estimate := transformed(truth, by, tt.scale)
+
+			alignment, scale, err := Align(estimate, truth, tt.withScale)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(scale*tt.scale-1) > 1e-9 {
+				t.Errorf("Wrong scale. Expected %v, got %v", 1/tt.scale, scale)
+			}
+			if !alignment.P.EqualsRotation(by.P.Conjugate(), 1e-9) {
+				t.Errorf("Wrong rotation. Expected %v, got %v", by.P.Conjugate(), alignment.P)
+			}
+
+			res, err := ATE(estimate, truth, ATEOptions{MaxTimeDifference: 0.01, Scale: tt.withScale})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Translation.Max > 1e-9 || res.Rotation.Max > 1e-7 || res.Translation.Count != 30 {
+				t.Errorf("Expected zero ATE after alignment, got %+v", res)
+			}
+		})
+	}
+}
+
+func TestATE_ShouldMeasureOffset(t *testing.T) {
+	truth := randomTrajectory(20)
+	estimate := make([]StampedPose, len(truth))
+	copy(estimate, truth)
+
+	for k, d := range map[int]float64{4: 0.5, 13: -0.5} {
+		p := estimate[k].Pose
+		estimate[k].Pose = quat.NewRigidBQuaternion(p.P, p.Translation().Sub(&quat.Vec3{Z: d}))
+	}
+
+	res, err := ATE(estimate, truth, ATEOptions{MaxTimeDifference: 0.01})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The alignment can only reduce the error of the unaligned estimate.
+	if unaligned := 0.5 * math.Sqrt(2.0/20); res.Translation.RMSE > unaligned || res.Translation.RMSE < unaligned/2 {
+		t.Errorf("Wrong ATE. Expected about %v, got %+v", unaligned, res.Translation)
+	}
+}
+
+func TestRPE_ShouldMeasureScaleDrift(t *testing.T) {
+	var truth, estimate []StampedPose
+	for k := 0; k <= 100; k++ {
+		r := quat.NewQuaternionByCoords(1, 0, 0, 0)
+		truth = append(truth, StampedPose{Time: float64(k), Pose: quat.NewRigidBQuaternion(r, &quat.Vec3{X: float64(k)})})
+		estimate = append(estimate, StampedPose{Time: float64(k) + 0.01, Pose: quat.NewRigidBQuaternion(r, &quat.Vec3{X: 1.1 * float64(k)})})
+	}
+
+	res, err := RPE(estimate, truth, RPEOptions{MaxTimeDifference: 0.05, Lengths: []float64{10, 50}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("Expected 2 segment lengths, got %v", len(res))
+	}
+	for _, s := range res {
+		want := 0.1 * s.Length
+		if math.Abs(s.Translation.Mean-want) > 1e-9 || math.Abs(s.Translation.Median-want) > 1e-9 || s.Rotation.Max != 0 {
+			t.Errorf("Wrong RPE for length %v. Expected %v, got %+v", s.Length, want, s.Errors)
+		}
+		if s.Translation.Count != 101-int(s.Length) {
+			t.Errorf("Wrong number of segments for length %v, got %v", s.Length, s.Translation.Count)
+		}
+	}
+}
+
+func TestRPE_ShouldRejectNonPositiveLength(t *testing.T) {
+	truth := randomTrajectory(10)
+
+	for _, length := range []float64{0, -1, math.NaN()} {
+		if _, err := RPE(truth, truth, RPEOptions{MaxTimeDifference: 0.01, Lengths: []float64{1, length}}); !errors.Is(err, SegmentLengthError) {
+			t.Errorf("Expected SegmentLengthError for length %v, got %v", length, err)
+		}
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	truth := []StampedPose{{Time: 0}, {Time: 1}, {Time: 2}, {Time: 3}}
+	estimate := []StampedPose{{Time: 0.9}, {Time: 1.6}, {Time: 3.05}, {Time: 7}}
+
+	est, gt := Associate(estimate, truth, 0.2)
+	if len(est) != 2 || gt[0].Time != 1 || gt[1].Time != 3 || est[1].Time != 3.05 {
+		t.Errorf("Wrong association, got %v and %v", est, gt)
+	}
+
+	// A faster estimate shares ground truth poses.
+	est, gt = Associate([]StampedPose{{Time: 0.95}, {Time: 1}, {Time: 1.05}}, truth, 0.2)
+	if len(est) != 3 || gt[0].Time != 1 || gt[1].Time != 1 || gt[2].Time != 1 {
+		t.Errorf("Wrong association of faster estimate, got %v and %v", est, gt)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	s := newStatistics([]float64{3, 1, 4, 2})
+	if s.Median != 2.5 || s.Mean != 2.5 || s.Max != 4 || math.Abs(s.RMSE-math.Sqrt(7.5)) > 1e-15 {
+		t.Errorf("Wrong statistics, got %+v", s)
+	}
+}
@@ -0,0 +1,58 @@
+package trajectory
+
+import (
+	"bufio"
+	quat "go-quaternions"
+	"io"
+	"strings"
+)
+
+// ReadKITTI reads one 3x4 row-major [R|t] matrix per line. The format has no
+// timestamps, so Time is the zero-based frame index.
+func ReadKITTI(r io.Reader) ([]StampedPose, error) {
+	var poses []StampedPose
+
+	err := readLines(r, func(fields []string) error {
+		v, err := parseFloats(fields, 12)
+		if err != nil {
+			return err
+		}
+
+		rotation := quat.NewQuaternionFromMatrix([3][3]float64{
+			{v[0], v[1], v[2]},
+			{v[4], v[5], v[6]},
+			{v[8], v[9], v[10]},
+		})
+		pose, err := newPose(rotation, &quat.Vec3{X: v[3], Y: v[7], Z: v[11]})
+		if err != nil {
+			return err
+		}
+		poses = append(poses, StampedPose{Time: float64(len(poses)), Pose: pose})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return poses, nil
+}
+
+// WriteKITTI writes poses as 3x4 matrices; timestamps are dropped.
+func WriteKITTI(w io.Writer, poses []StampedPose) error {
+	bw := bufio.NewWriter(w)
+
+	for _, p := range poses {
+		m := p.Pose.Compile()
+
+		fields := make([]string, 0, 12)
+		for r, t := range [3]float64{m.T.X, m.T.Y, m.T.Z} {
+			fields = append(fields, formatFloat(m.R[r][0]), formatFloat(m.R[r][1]), formatFloat(m.R[r][2]), formatFloat(t))
+		}
+		if _, err := bw.WriteString(strings.Join(fields, " ") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
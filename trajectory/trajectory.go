@@ -0,0 +1,102 @@
+// Package trajectory reads and writes timestamped pose sequences in the TUM,
+// KITTI and EuRoC text formats.
+//
+// Every format is converted to StampedPose, whose Pose maps points from the
+// body frame to the world frame as in quat.NewRigidBQuaternion. The component
+// order of quaternions differs between formats and is handled here:
+//
+//	TUM    t tx ty tz qx qy qz qw     (scalar last)
+//	KITTI  r11 r12 r13 tx r21 ... tz  (3x4 matrix rows, no timestamps)
+//	EuRoC  t[ns], tx, ty, tz, qw, qx, qy, qz, ...  (scalar first)
+package trajectory
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var MalformedLineError = errors.WithStack(errors.New("Malformed trajectory line"))
+
+type StampedPose struct {
+	// Time is in seconds.
+	Time float64
+	// Nanoseconds keeps integer timestamps exactly, which float64 seconds
+	// cannot for epoch stamps such as EuRoC's. When it is non-zero it takes
+	// precedence over Time in every writer, so a caller changing Time must
+	// also set or clear Nanoseconds. Zero means Time alone holds the stamp.
+	Nanoseconds int64
+	Pose        *quat.BQuaternion
+}
+
+// seconds returns the timestamp in seconds, from Nanoseconds when set.
+func (p StampedPose) seconds() float64 {
+	if p.Nanoseconds != 0 {
+		return float64(p.Nanoseconds) * 1e-9
+	}
+
+	return p.Time
+}
+
+// nanoseconds returns the timestamp in integer nanoseconds.
+func (p StampedPose) nanoseconds() int64 {
+	if p.Nanoseconds != 0 {
+		return p.Nanoseconds
+	}
+
+	return int64(math.Round(p.Time * 1e9))
+}
+
+// readLines calls parse with the fields of every line that is neither empty
+// nor a comment, splitting on whitespace and commas.
+func readLines(r io.Reader, parse func(fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})
+		if err := parse(fields); err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func parseFloats(fields []string, n int) ([]float64, error) {
+	if len(fields) != n {
+		return nil, errors.Wrapf(MalformedLineError, "expected %d values, got %d", n, len(fields))
+	}
+
+	v := make([]float64, n)
+	for k := range v {
+		var err error
+		if v[k], err = strconv.ParseFloat(fields[k], 64); err != nil {
+			return nil, errors.Wrap(MalformedLineError, err.Error())
+		}
+	}
+
+	return v, nil
+}
+
+func newPose(rotation *quat.Quaternion, translation *quat.Vec3) (*quat.BQuaternion, error) {
+	normRotation, err := rotation.Normalize()
+	if err != nil {
+		return nil, errors.Wrap(MalformedLineError, err.Error())
+	}
+
+	return quat.NewRigidBQuaternion(normRotation, translation), nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
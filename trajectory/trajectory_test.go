@@ -0,0 +1,198 @@
+package trajectory
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"math"
+	"strings"
+	"testing"
+)
+
+// quarterTurn rotates by 90 degrees around Z, so it maps X to Y.
+var quarterTurn = quat.NewQuaternionByCoords(math.Sqrt2/2, 0, 0, math.Sqrt2/2)
+
+func checkQuarterTurn(t *testing.T, p StampedPose, time float64) {
+	t.Helper()
+
+	if math.Abs(p.Time-time) > 1e-9 {
+		t.Errorf("Wrong timestamp. Expected %v, got %v", time, p.Time)
+	}
+	if !p.Pose.P.EqualsRotation(quarterTurn, 1e-12) {
+		t.Errorf("Wrong rotation. Expected %v, got %v", quarterTurn, p.Pose.P)
+	}
+	want := &quat.Vec3{X: 1, Y: 3, Z: 3}
+	if got := p.Pose.Compile().Apply(&quat.Vec3{X: 1}); !got.Equals(want, 1e-12) {
+		t.Errorf("Wrong transform of X axis. Expected %v, got %v", want, got)
+	}
+}
+
+func TestReadTUM(t *testing.T) {
+	poses, err := ReadTUM(strings.NewReader("# timestamp tx ty tz qx qy qz qw\n1.5 1 2 3 0 0 0.7071067811865476 0.7071067811865476\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poses) != 1 {
+		t.Fatalf("Expected 1 pose, got %v", len(poses))
+	}
+	checkQuarterTurn(t, poses[0], 1.5)
+}
+
+func TestReadKITTI(t *testing.T) {
+	poses, err := ReadKITTI(strings.NewReader("1 0 0 0 0 1 0 0 0 0 1 0\n0 -1 0 1 1 0 0 2 0 0 1 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poses) != 2 {
+		t.Fatalf("Expected 2 poses, got %v", len(poses))
+	}
+	checkQuarterTurn(t, poses[1], 1)
+}
+
+func TestReadEuRoC(t *testing.T) {
+	data := eurocHeader + ", v_RS_R_x [m s^-1]\n1403636579758555392, 1, 2, 3, 0.7071067811865476, 0, 0, 0.7071067811865476, 0.1\n"
+
+	poses, err := ReadEuRoC(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(poses) != 1 {
+		t.Fatalf("Expected 1 pose, got %v", len(poses))
+	}
+	checkQuarterTurn(t, poses[0], 1403636579.758555392)
+}
+
+func TestRead_ShouldReportLine(t *testing.T) {
+	_, err := ReadTUM(strings.NewReader("1 0 0 0 0 0 0 1\n2 0 0 0 0 0 0\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected error on line 2, got %v", err)
+	}
+
+	_, err = ReadTUM(strings.NewReader("1 0 0 0 0 0 0 0\n"))
+	if err == nil {
+		t.Errorf("Expected error for zero quaternion")
+	}
+}
+
+func TestRead_ShouldRejectExtraColumns(t *testing.T) {
+	if _, err := ReadTUM(strings.NewReader("1 0 0 0 0 0 0 1 5\n")); !errors.Is(err, MalformedLineError) {
+		t.Errorf("Expected MalformedLineError for 9 TUM columns, got %v", err)
+	}
+	if _, err := ReadKITTI(strings.NewReader("1 0 0 0 0 1 0 0 0 0 1 0 7\n")); !errors.Is(err, MalformedLineError) {
+		t.Errorf("Expected MalformedLineError for 13 KITTI columns, got %v", err)
+	}
+}
+
+func TestWrite_ShouldRoundTrip(t *testing.T) {
+	poses := []StampedPose{
+		{Time: 0.25, Pose: quat.NewRigidBQuaternion(quarterTurn, &quat.Vec3{X: 1, Y: 2, Z: 3})},
+		{Time: 0.5, Pose: quat.NewRigidBQuaternion(quat.NewQuaternionByCoords(0.5, -0.5, 0.5, 0.5), &quat.Vec3{X: -1, Y: 0, Z: 0.5})},
+	}
+
+	tests := []struct {
+		name  string
+		write func(w *bytes.Buffer, poses []StampedPose) error
+		read  func(r *bytes.Buffer) ([]StampedPose, error)
+		times bool
+	}{
+		{
+			name:  "TUM",
+			write: func(w *bytes.Buffer, poses []StampedPose) error { return WriteTUM(w, poses) },
+			read:  func(r *bytes.Buffer) ([]StampedPose, error) { return ReadTUM(r) },
+			times: true,
+		},
+		{
+			name:  "KITTI",
+			write: func(w *bytes.Buffer, poses []StampedPose) error { return WriteKITTI(w, poses) },
+			read:  func(r *bytes.Buffer) ([]StampedPose, error) { return ReadKITTI(r) },
+		},
+		{
+			name:  "EuRoC",
+			write: func(w *bytes.Buffer, poses []StampedPose) error { return WriteEuRoC(w, poses) },
+			read:  func(r *bytes.Buffer) ([]StampedPose, error) { return ReadEuRoC(r) },
+			times: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf, poses); err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.read(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(poses) {
+				t.Fatalf("Expected %v poses, got %v", len(poses), len(got))
+			}
+
+			for k := range poses {
+				if tt.times && math.Abs(got[k].Time-poses[k].Time) > 1e-9 {
+					t.Errorf("Wrong timestamp at %v. Expected %v, got %v", k, poses[k].Time, got[k].Time)
+				}
+				d, err := got[k].Pose.Distance(poses[k].Pose, 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d > 1e-12 {
+					t.Errorf("Wrong pose at %v. Expected %v, got %v", k, poses[k].Pose, got[k].Pose)
+				}
+			}
+		})
+	}
+}
+
+func TestEuRoC_ShouldKeepNanoseconds(t *testing.T) {
+	stamps := []int64{1403636579763555584, 1403636579768555520, 1403636579773555456}
+	poses := make([]StampedPose, len(stamps))
+	for k, ns := range stamps {
+		poses[k] = StampedPose{Nanoseconds: ns, Pose: quat.NewRigidBQuaternion(quarterTurn, &quat.Vec3{X: float64(k)})}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEuRoC(&buf, poses); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n1403636579763555584,") {
+		t.Errorf("Wrong timestamp written. Expected 1403636579763555584 in %q", buf.String())
+	}
+
+	got, err := ReadEuRoC(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(stamps) {
+		t.Fatalf("Expected %v poses, got %v", len(stamps), len(got))
+	}
+	for k, ns := range stamps {
+		if got[k].Nanoseconds != ns {
+			t.Errorf("Wrong timestamp at %v. Expected %v, got %v", k, ns, got[k].Nanoseconds)
+		}
+	}
+}
+
+func TestWrite_NanosecondsShouldTakePrecedence(t *testing.T) {
+	poses := []StampedPose{{Time: 7, Nanoseconds: 1403636579763555584, Pose: quat.NewRigidBQuaternion(quarterTurn, &quat.Vec3{})}}
+
+	var buf bytes.Buffer
+	if err := WriteTUM(&buf, poses); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadTUM(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got[0].Time-1403636579.763555584) > 1e-6 {
+		t.Errorf("Wrong timestamp. Expected %v, got %v", 1403636579.763555584, got[0].Time)
+	}
+
+	poses[0].Nanoseconds = 0
+	buf.Reset()
+	if err := WriteEuRoC(&buf, poses); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n7000000000,") {
+		t.Errorf("Wrong timestamp written. Expected 7000000000 in %q", buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package trajectory
+
+import (
+	"bufio"
+	quat "go-quaternions"
+	"io"
+	"strings"
+)
+
+func ReadTUM(r io.Reader) ([]StampedPose, error) {
+	var poses []StampedPose
+
+	err := readLines(r, func(fields []string) error {
+		v, err := parseFloats(fields, 8)
+		if err != nil {
+			return err
+		}
+
+		pose, err := newPose(quat.NewQuaternionByCoords(v[7], v[4], v[5], v[6]), &quat.Vec3{X: v[1], Y: v[2], Z: v[3]})
+		if err != nil {
+			return err
+		}
+		poses = append(poses, StampedPose{Time: v[0], Pose: pose})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return poses, nil
+}
+
+func WriteTUM(w io.Writer, poses []StampedPose) error {
+	bw := bufio.NewWriter(w)
+
+	for _, p := range poses {
+		t, q := p.Pose.Translation(), p.Pose.P
+		values := []float64{p.seconds(), t.X, t.Y, t.Z, q.I, q.J, q.K, q.W}
+
+		fields := make([]string, len(values))
+		for k, v := range values {
+			fields[k] = formatFloat(v)
+		}
+		if _, err := bw.WriteString(strings.Join(fields, " ") + "\n"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,95 @@
+package go_quaternions
+
+import "math"
+
+// Transform is a rotation (or rigid motion) compiled once into a 3x3 matrix
+// and a translation, so that applying it to a point costs 9 multiplications
+// and 9 additions instead of two BQuaternion products.
+type Transform struct {
+	R [3][3]float64
+	T Vec3
+}
+
+// Compile expands the sandwich product q*v*q' into a matrix. For a unit
+// quaternion the result is a pure rotation, exactly as in RotateRad.
+func (q *Quaternion) Compile() *Transform {
+	return &Transform{
+		R: sandwichMatrix(q),
+	}
+}
+
+// Compile expands bq*v*bq' (with the conjugate used by the Vec3 helpers)
+// into a matrix and a translation, so every rigid BQuaternion built in
+// vec3.go compiles to the same mapping it applies point by point.
+func (bq *BQuaternion) Compile() *Transform {
+	return &Transform{
+		R: sandwichMatrix(bq.P),
+		T: *bq.Translation(),
+	}
+}
+
+// NewQuaternionFromMatrix returns the unit quaternion of a rotation matrix,
+// choosing the numerically largest component first (Shepperd's method).
+func NewQuaternionFromMatrix(r [3][3]float64) *Quaternion {
+	trace := r[0][0] + r[1][1] + r[2][2]
+
+	var q *Quaternion
+	switch {
+	case trace > r[0][0] && trace > r[1][1] && trace > r[2][2]:
+		s := 2 * math.Sqrt(1+trace)
+		q = NewQuaternionByCoords(s/4, (r[2][1]-r[1][2])/s, (r[0][2]-r[2][0])/s, (r[1][0]-r[0][1])/s)
+	case r[0][0] >= r[1][1] && r[0][0] >= r[2][2]:
+		s := 2 * math.Sqrt(1+r[0][0]-r[1][1]-r[2][2])
+		q = NewQuaternionByCoords((r[2][1]-r[1][2])/s, s/4, (r[0][1]+r[1][0])/s, (r[0][2]+r[2][0])/s)
+	case r[1][1] >= r[2][2]:
+		s := 2 * math.Sqrt(1-r[0][0]+r[1][1]-r[2][2])
+		q = NewQuaternionByCoords((r[0][2]-r[2][0])/s, (r[0][1]+r[1][0])/s, s/4, (r[1][2]+r[2][1])/s)
+	default:
+		s := 2 * math.Sqrt(1-r[0][0]-r[1][1]+r[2][2])
+		q = NewQuaternionByCoords((r[1][0]-r[0][1])/s, (r[0][2]+r[2][0])/s, (r[1][2]+r[2][1])/s, s/4)
+	}
+
+	if normQ, err := q.Normalize(); err == nil {
+		q = normQ
+	}
+
+	return q.Canonical()
+}
+
+// BQuaternion converts a rigid transform back to the form Compile accepts.
+func (t *Transform) BQuaternion() *BQuaternion {
+	return NewRigidBQuaternion(NewQuaternionFromMatrix(t.R), &t.T)
+}
+
+func (t *Transform) Apply(v *Vec3) *Vec3 {
+	return &Vec3{
+		X: t.R[0][0]*v.X + t.R[0][1]*v.Y + t.R[0][2]*v.Z + t.T.X,
+		Y: t.R[1][0]*v.X + t.R[1][1]*v.Y + t.R[1][2]*v.Z + t.T.Y,
+		Z: t.R[2][0]*v.X + t.R[2][1]*v.Y + t.R[2][2]*v.Z + t.T.Z,
+	}
+}
+
+// ApplyAll transforms points in place and returns the same slice.
+func (t *Transform) ApplyAll(points []Vec3) []Vec3 {
+	for n := range points {
+		v := &points[n]
+		x := t.R[0][0]*v.X + t.R[0][1]*v.Y + t.R[0][2]*v.Z + t.T.X
+		y := t.R[1][0]*v.X + t.R[1][1]*v.Y + t.R[1][2]*v.Z + t.T.Y
+		z := t.R[2][0]*v.X + t.R[2][1]*v.Y + t.R[2][2]*v.Z + t.T.Z
+		v.X, v.Y, v.Z = x, y, z
+	}
+
+	return points
+}
+
+func sandwichMatrix(q *Quaternion) [3][3]float64 {
+	ww, ii, jj, kk := q.W*q.W, q.I*q.I, q.J*q.J, q.K*q.K
+	wi, wj, wk := q.W*q.I, q.W*q.J, q.W*q.K
+	ij, ik, jk := q.I*q.J, q.I*q.K, q.J*q.K
+
+	return [3][3]float64{
+		{ww + ii - jj - kk, 2 * (ij - wk), 2 * (ik + wj)},
+		{2 * (ij + wk), ww - ii + jj - kk, 2 * (jk - wi)},
+		{2 * (ik - wj), 2 * (jk + wi), ww - ii - jj + kk},
+	}
+}
@@ -0,0 +1,228 @@
+package go_quaternions
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTransform_ApplyShouldMatchRotateRad(t *testing.T) {
+	tests := []struct {
+		name  string
+		eps   float64
+		axis  *Vec3
+		angle float64
+	}{
+		{
+			name:  "rotate by X axis",
+			eps:   1e-12,
+			axis:  &Vec3{X: 1, Y: 0, Z: 0},
+			angle: math.Pi / 3,
+		},
+		{
+			name:  "rotate by Z axis",
+			eps:   1e-12,
+			axis:  &Vec3{X: 0, Y: 0, Z: 1},
+			angle: -math.Pi / 2,
+		},
+		{
+			name:  "rotate by random axis",
+			eps:   1e-12,
+			axis:  &Vec3{X: rand.Float64() - 0.5, Y: rand.Float64() - 0.5, Z: rand.Float64() - 0.5},
+			angle: -2*math.Pi + rand.Float64()*(4*math.Pi),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQuaternionByCoords(0, tt.axis.X, tt.axis.Y, tt.axis.Z).ToRotateQuaternion(tt.angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			op := q.Compile()
+
+			for n := 0; n < 10; n++ {
+				p := &Vec3{X: rand.Float64() - 0.5, Y: rand.Float64() - 0.5, Z: rand.Float64() - 0.5}
+
+				want, err := p.RotateRad(tt.axis, tt.angle)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := op.Apply(p); !got.Equals(want, tt.eps) {
+					t.Errorf("Wrong result of rotate %v around %v. Expected %v, got %v", p, tt.axis, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform_ApplyShouldMatchRigidMotion(t *testing.T) {
+	tests := []struct {
+		name      string
+		eps       float64
+		step      *Vec3
+		axis      *Vec3
+		angle     float64
+		stepFirst bool
+	}{
+		{
+			name:  "rotate and step",
+			eps:   1e-12,
+			step:  &Vec3{X: 1, Y: -2, Z: 3},
+			axis:  &Vec3{X: 0, Y: 1, Z: 1},
+			angle: math.Pi / 5,
+		},
+		{
+			name:      "step and rotate",
+			eps:       1e-12,
+			step:      &Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()},
+			axis:      &Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()},
+			angle:     rand.Float64() * 2 * math.Pi,
+			stepFirst: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewQuaternionByCoords(0, tt.axis.X, tt.axis.Y, tt.axis.Z).ToRotateQuaternion(tt.angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := NewQuaternionByCoords(0, tt.step.X/2, tt.step.Y/2, tt.step.Z/2)
+
+			bq := NewBQuaternion(r, s.MulByGrassmann(r))
+			if tt.stepFirst {
+				bq = NewBQuaternion(r, r.MulByGrassmann(s))
+			}
+			op := bq.Compile()
+
+			for n := 0; n < 10; n++ {
+				p := &Vec3{X: rand.Float64() - 0.5, Y: rand.Float64() - 0.5, Z: rand.Float64() - 0.5}
+
+				want, err := p.RotateAndStepTo(tt.step, tt.axis, tt.angle)
+				if tt.stepFirst {
+					want, err = p.StepAndRotateTo(tt.step, tt.axis, tt.angle)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := op.Apply(p); !got.Equals(want, tt.eps) {
+					t.Errorf("Wrong result of motion for %v. Expected %v, got %v", p, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform_ApplyAll(t *testing.T) {
+	q, err := NewQuaternionByCoords(0, 1, 2, 3).ToRotateQuaternion(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := NewBQuaternion(q, NewQuaternionByCoords(0, 0.5, 0, 0).MulByGrassmann(q)).Compile()
+
+	points := []Vec3{{X: 1}, {Y: 1}, {Z: 1}, {X: 1, Y: 2, Z: 3}}
+	want := make([]*Vec3, len(points))
+	for n := range points {
+		want[n] = op.Apply(&points[n])
+	}
+
+	op.ApplyAll(points)
+
+	for n := range points {
+		if !points[n].Equals(want[n], EqualsEpsilon) {
+			t.Errorf("Wrong result of ApplyAll at %v. Expected %v, got %v", n, want[n], &points[n])
+		}
+	}
+}
+
+func TestNewQuaternionFromMatrix(t *testing.T) {
+	tests := []struct {
+		name  string
+		axis  *Vec3
+		angle float64
+	}{
+		{name: "identity", axis: &Vec3{X: 1}, angle: 0},
+		{name: "half turn around X", axis: &Vec3{X: 1}, angle: math.Pi},
+		{name: "half turn around Y", axis: &Vec3{Y: 1}, angle: math.Pi},
+		{name: "half turn around Z", axis: &Vec3{Z: 1}, angle: math.Pi},
+		{name: "near half turn", axis: &Vec3{X: 1, Y: -1, Z: 0.1}, angle: math.Pi - 1e-9},
+		{name: "random", axis: &Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()}, angle: rand.Float64() * 2 * math.Pi},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQuaternionByCoords(0, tt.axis.X, tt.axis.Y, tt.axis.Z).ToRotateQuaternion(tt.angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := NewQuaternionFromMatrix(q.Compile().R)
+			if !got.EqualsRotation(q, 1e-12) || math.Abs(got.Norm()-1) > 1e-15 {
+				t.Errorf("Wrong quaternion from matrix. Expected %v, got %v", q, got)
+			}
+
+			bq := NewRigidBQuaternion(q, &Vec3{X: 1, Y: -2, Z: 3})
+			if back := bq.Compile().BQuaternion(); !back.Compile().Apply(&Vec3{X: 1}).Equals(bq.Compile().Apply(&Vec3{X: 1}), 1e-12) {
+				t.Errorf("Wrong rigid motion from transform. Expected %v, got %v", bq, back)
+			}
+		})
+	}
+}
+
+func BenchmarkVec3_RotateRad(b *testing.B) {
+	axis := &Vec3{X: 1, Y: 2, Z: 3}
+	p := &Vec3{X: 0.3, Y: -0.2, Z: 0.1}
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if _, err := p.RotateRad(axis, 0.7); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTransform_Apply(b *testing.B) {
+	q, err := NewQuaternionByCoords(0, 1, 2, 3).ToRotateQuaternion(0.7)
+	if err != nil {
+		b.Fatal(err)
+	}
+	op := q.Compile()
+	p := &Vec3{X: 0.3, Y: -0.2, Z: 0.1}
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		op.Apply(p)
+	}
+}
+
+func BenchmarkBQuaternion_MulSandwich(b *testing.B) {
+	q, err := NewQuaternionByCoords(0, 1, 2, 3).ToRotateQuaternion(0.7)
+	if err != nil {
+		b.Fatal(err)
+	}
+	bq := NewBQuaternion(q, NewQuaternionByCoords(0, 0.5, -1, 2).MulByGrassmann(q))
+	bqc := bq.Conjugate().ComplexConjugate()
+	p := NewBQuaternion(NewQuaternionByCoords(1, 0, 0, 0), NewQuaternionByCoords(0, 0.3, -0.2, 0.1))
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		bq.Mul(p).Mul(bqc)
+	}
+}
+
+func BenchmarkTransform_ApplyAll(b *testing.B) {
+	q, err := NewQuaternionByCoords(0, 1, 2, 3).ToRotateQuaternion(0.7)
+	if err != nil {
+		b.Fatal(err)
+	}
+	op := NewBQuaternion(q, NewQuaternionByCoords(0, 0.5, -1, 2).MulByGrassmann(q)).Compile()
+	points := make([]Vec3, 1024)
+	for n := range points {
+		points[n] = Vec3{X: rand.Float64(), Y: rand.Float64(), Z: rand.Float64()}
+	}
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		op.ApplyAll(points)
+	}
+}
@@ -0,0 +1,358 @@
+// Package urdf reads robot descriptions in the Unified Robot Description
+// Format and computes the poses of their links.
+//
+// Only the kinematic part of a description is used: links, and joints with
+// their origins, axes and limits. Origins become BQuaternion transforms with
+// the rotation of rpy = (r, p, y) being Rz(y)*Ry(p)*Rx(r), as in URDF.
+package urdf
+
+import (
+	"encoding/xml"
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"go-quaternions/kinematics"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	MalformedURDFError    = errors.WithStack(errors.New("Malformed URDF"))
+	UnsupportedJointError = errors.WithStack(errors.New("Unsupported joint type"))
+	UnknownLinkError      = errors.WithStack(errors.New("Unknown link"))
+	UnknownJointError     = errors.WithStack(errors.New("Unknown joint"))
+	NotConnectedError     = errors.WithStack(errors.New("Links are not connected"))
+)
+
+type JointType int
+
+const (
+	Revolute JointType = iota
+	Continuous
+	Prismatic
+	Fixed
+)
+
+var jointTypes = map[string]JointType{
+	"revolute":   Revolute,
+	"continuous": Continuous,
+	"prismatic":  Prismatic,
+	"fixed":      Fixed,
+}
+
+type Link struct {
+	Name string
+	// Parent is the joint connecting the link to its parent, nil for the
+	// root.
+	Parent   *Joint
+	Children []*Joint
+}
+
+type Joint struct {
+	Name   string
+	Type   JointType
+	Parent *Link
+	Child  *Link
+	// Origin is the transform from the parent link to the joint frame.
+	Origin *quat.BQuaternion
+	// Axis is the unit axis in the joint frame.
+	Axis *quat.Vec3
+	// Lower and Upper are the limits of revolute and prismatic joints.
+	Lower, Upper float64
+}
+
+type Robot struct {
+	Name   string
+	Root   *Link
+	Links  map[string]*Link
+	Joints map[string]*Joint
+}
+
+type robotXML struct {
+	Name   string     `xml:"name,attr"`
+	Links  []linkXML  `xml:"link"`
+	Joints []jointXML `xml:"joint"`
+}
+
+type linkXML struct {
+	Name string `xml:"name,attr"`
+}
+
+type jointXML struct {
+	Name   string `xml:"name,attr"`
+	Type   string `xml:"type,attr"`
+	Origin *struct {
+		XYZ string `xml:"xyz,attr"`
+		RPY string `xml:"rpy,attr"`
+	} `xml:"origin"`
+	Parent struct {
+		Link string `xml:"link,attr"`
+	} `xml:"parent"`
+	Child struct {
+		Link string `xml:"link,attr"`
+	} `xml:"child"`
+	Axis *struct {
+		XYZ string `xml:"xyz,attr"`
+	} `xml:"axis"`
+	Limit *struct {
+		Lower float64 `xml:"lower,attr"`
+		Upper float64 `xml:"upper,attr"`
+	} `xml:"limit"`
+}
+
+// Read parses a URDF document. The links must form a single tree.
+func Read(r io.Reader) (*Robot, error) {
+	var doc robotXML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(MalformedURDFError, err.Error())
+	}
+
+	robot := &Robot{Name: doc.Name, Links: map[string]*Link{}, Joints: map[string]*Joint{}}
+	for _, l := range doc.Links {
+		if _, ok := robot.Links[l.Name]; ok {
+			return nil, errors.Wrapf(MalformedURDFError, "duplicate link %q", l.Name)
+		}
+		robot.Links[l.Name] = &Link{Name: l.Name}
+	}
+
+	for _, j := range doc.Joints {
+		joint, err := robot.newJoint(j)
+		if err != nil {
+			return nil, errors.Wrapf(err, "joint %q", j.Name)
+		}
+		robot.Joints[joint.Name] = joint
+	}
+
+	for _, l := range doc.Links {
+		link := robot.Links[l.Name]
+		if link.Parent != nil {
+			continue
+		}
+		if robot.Root != nil {
+			return nil, errors.Wrapf(MalformedURDFError, "links %q and %q have no parent", robot.Root.Name, link.Name)
+		}
+		robot.Root = link
+	}
+	if robot.Root == nil {
+		return nil, errors.Wrap(MalformedURDFError, "no root link")
+	}
+	if n := robot.countLinks(robot.Root); n != len(robot.Links) {
+		return nil, errors.Wrapf(MalformedURDFError, "%d links are not reachable from %q", len(robot.Links)-n, robot.Root.Name)
+	}
+
+	return robot, nil
+}
+
+func (r *Robot) newJoint(j jointXML) (*Joint, error) {
+	if _, ok := r.Joints[j.Name]; ok {
+		return nil, errors.Wrap(MalformedURDFError, "duplicate joint")
+	}
+
+	jointType, ok := jointTypes[j.Type]
+	if !ok {
+		return nil, errors.Wrapf(UnsupportedJointError, "%q", j.Type)
+	}
+
+	parent, ok := r.Links[j.Parent.Link]
+	if !ok {
+		return nil, errors.Wrapf(UnknownLinkError, "parent %q", j.Parent.Link)
+	}
+	child, ok := r.Links[j.Child.Link]
+	if !ok {
+		return nil, errors.Wrapf(UnknownLinkError, "child %q", j.Child.Link)
+	}
+	if child.Parent != nil {
+		return nil, errors.Wrapf(MalformedURDFError, "link %q already has parent joint %q", child.Name, child.Parent.Name)
+	}
+
+	joint := &Joint{Name: j.Name, Type: jointType, Parent: parent, Child: child, Axis: &quat.Vec3{X: 1}}
+
+	xyz, rpy := []float64{0, 0, 0}, []float64{0, 0, 0}
+	if j.Origin != nil {
+		var err error
+		if xyz, err = parseTriple(j.Origin.XYZ); err != nil {
+			return nil, errors.Wrap(err, "origin xyz")
+		}
+		if rpy, err = parseTriple(j.Origin.RPY); err != nil {
+			return nil, errors.Wrap(err, "origin rpy")
+		}
+	}
+	joint.Origin = quat.NewRigidBQuaternion(fromRPY(rpy[0], rpy[1], rpy[2]), &quat.Vec3{X: xyz[0], Y: xyz[1], Z: xyz[2]})
+
+	if j.Axis != nil {
+		v, err := parseTriple(j.Axis.XYZ)
+		if err != nil {
+			return nil, errors.Wrap(err, "axis")
+		}
+		axis := &quat.Vec3{X: v[0], Y: v[1], Z: v[2]}
+		l := axis.Length()
+		if l == 0 {
+			return nil, errors.Wrap(MalformedURDFError, "zero axis")
+		}
+		joint.Axis = &quat.Vec3{X: axis.X / l, Y: axis.Y / l, Z: axis.Z / l}
+	}
+
+	if j.Limit != nil {
+		joint.Lower, joint.Upper = j.Limit.Lower, j.Limit.Upper
+	}
+
+	parent.Children = append(parent.Children, joint)
+	child.Parent = joint
+
+	return joint, nil
+}
+
+func (r *Robot) countLinks(link *Link) int {
+	n := 1
+	for _, j := range link.Children {
+		n += r.countLinks(j.Child)
+	}
+
+	return n
+}
+
+// Transform returns the transform from the parent link to the child link at
+// joint position q, which is ignored for fixed joints.
+func (j *Joint) Transform(q float64) (*quat.BQuaternion, error) {
+	if j.Type == Fixed {
+		return j.Origin, nil
+	}
+
+	joint, err := j.kinematic(j.Origin)
+	if err != nil {
+		return nil, err
+	}
+
+	return joint.Transform(q), nil
+}
+
+func (j *Joint) kinematic(origin *quat.BQuaternion) (*kinematics.Joint, error) {
+	jointType := kinematics.Revolute
+	if j.Type == Prismatic {
+		jointType = kinematics.Prismatic
+	}
+
+	joint, err := kinematics.NewAxisJoint(jointType, origin, j.Axis)
+	if err != nil {
+		return nil, errors.Wrapf(err, "joint %q", j.Name)
+	}
+	joint.Name = j.Name
+	if j.Type == Revolute || j.Type == Prismatic {
+		joint.SetLimits(j.Lower, j.Upper)
+	}
+
+	return joint, nil
+}
+
+// Poses returns the pose of every link in the frame of the root link.
+// Joints missing from positions are at zero.
+func (r *Robot) Poses(positions map[string]float64) (map[string]*quat.BQuaternion, error) {
+	for name := range positions {
+		if _, ok := r.Joints[name]; !ok {
+			return nil, errors.Wrapf(UnknownJointError, "%q", name)
+		}
+	}
+
+	poses := map[string]*quat.BQuaternion{r.Root.Name: identity()}
+	var walk func(link *Link) error
+	walk = func(link *Link) error {
+		for _, j := range link.Children {
+			tf, err := j.Transform(positions[j.Name])
+			if err != nil {
+				return err
+			}
+			poses[j.Child.Name] = poses[link.Name].Mul(tf)
+			if err := walk(j.Child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := walk(r.Root); err != nil {
+		return nil, err
+	}
+
+	return poses, nil
+}
+
+// Chain converts the path from base to tip into a serial chain for the
+// kinematics package. Fixed joints are folded into the origin of the next
+// movable joint or into the tool transform; the names of the movable
+// joints are returned in chain order.
+func (r *Robot) Chain(base, tip string) (*kinematics.Chain, []string, error) {
+	if _, ok := r.Links[base]; !ok {
+		return nil, nil, errors.Wrapf(UnknownLinkError, "%q", base)
+	}
+	link, ok := r.Links[tip]
+	if !ok {
+		return nil, nil, errors.Wrapf(UnknownLinkError, "%q", tip)
+	}
+
+	var path []*Joint
+	for ; link.Name != base; link = link.Parent.Parent {
+		if link.Parent == nil {
+			return nil, nil, errors.Wrapf(NotConnectedError, "%q is not above %q", base, tip)
+		}
+		path = append(path, link.Parent)
+	}
+
+	chain := kinematics.NewChain()
+	var names []string
+	pending := identity()
+	for k := len(path) - 1; k >= 0; k-- {
+		j := path[k]
+		if j.Type == Fixed {
+			pending = pending.Mul(j.Origin)
+			continue
+		}
+
+		joint, err := j.kinematic(pending.Mul(j.Origin))
+		if err != nil {
+			return nil, nil, err
+		}
+		chain.Joints = append(chain.Joints, joint)
+		names = append(names, j.Name)
+		pending = identity()
+	}
+	chain.Tool = pending
+
+	return chain, names, nil
+}
+
+// fromRPY returns the rotation about fixed X, Y and Z axes by roll, pitch
+// and yaw, in that order.
+func fromRPY(roll, pitch, yaw float64) *quat.Quaternion {
+	x := quat.NewQuaternionByCoords(math.Cos(roll/2), math.Sin(roll/2), 0, 0)
+	y := quat.NewQuaternionByCoords(math.Cos(pitch/2), 0, math.Sin(pitch/2), 0)
+	z := quat.NewQuaternionByCoords(math.Cos(yaw/2), 0, 0, math.Sin(yaw/2))
+
+	return z.MulByGrassmann(y).MulByGrassmann(x)
+}
+
+// parseTriple reads three space separated numbers; an empty attribute is
+// zero, as in URDF.
+func parseTriple(s string) ([]float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return []float64{0, 0, 0}, nil
+	}
+	if len(fields) != 3 {
+		return nil, errors.Wrapf(MalformedURDFError, "expected 3 values, got %q", s)
+	}
+
+	v := make([]float64, 3)
+	for k, f := range fields {
+		var err error
+		if v[k], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, errors.Wrap(MalformedURDFError, err.Error())
+		}
+	}
+
+	return v, nil
+}
+
+func identity() *quat.BQuaternion {
+	return quat.NewBQuaternion(quat.NewQuaternionByCoords(1, 0, 0, 0), quat.NewQuaternionByCoords(0, 0, 0, 0))
+}
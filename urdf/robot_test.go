@@ -0,0 +1,216 @@
+package urdf
+
+import (
+	"github.com/pkg/errors"
+	quat "go-quaternions"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const arm = `<?xml version="1.0"?>
+<robot name="arm">
+  <link name="base"/>
+  <link name="shoulder"/>
+  <link name="upper"/>
+  <link name="slider"/>
+  <link name="tool"/>
+  <link name="camera"/>
+
+  <joint name="yaw" type="continuous">
+    <parent link="base"/>
+    <child link="shoulder"/>
+    <origin xyz="0 0 0.5"/>
+    <axis xyz="0 0 1"/>
+  </joint>
+  <joint name="pitch" type="revolute">
+    <parent link="shoulder"/>
+    <child link="upper"/>
+    <origin xyz="0 0 0" rpy="1.5707963267948966 0 0"/>
+    <axis xyz="0 0 2"/>
+    <limit lower="-1" upper="1" effort="10" velocity="1"/>
+  </joint>
+  <joint name="extend" type="prismatic">
+    <parent link="upper"/>
+    <child link="slider"/>
+    <origin xyz="1 0 0"/>
+    <limit lower="0" upper="0.5"/>
+  </joint>
+  <joint name="flange" type="fixed">
+    <parent link="slider"/>
+    <child link="tool"/>
+    <origin xyz="0.1 0 0"/>
+  </joint>
+  <joint name="mount" type="fixed">
+    <parent link="shoulder"/>
+    <child link="camera"/>
+    <origin xyz="0 0.2 0" rpy="0 0 3.141592653589793"/>
+  </joint>
+</robot>`
+
+func readArm(t *testing.T) *Robot {
+	robot, err := Read(strings.NewReader(arm))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return robot
+}
+
+func TestRead(t *testing.T) {
+	robot := readArm(t)
+
+	if robot.Name != "arm" || robot.Root.Name != "base" || len(robot.Links) != 6 || len(robot.Joints) != 5 {
+		t.Fatalf("Wrong result of Read: %v with root %v, %d links and %d joints", robot.Name, robot.Root.Name, len(robot.Links), len(robot.Joints))
+	}
+
+	pitch := robot.Joints["pitch"]
+	if pitch.Type != Revolute || pitch.Lower != -1 || pitch.Upper != 1 || *pitch.Axis != (quat.Vec3{Z: 1}) {
+		t.Errorf("Wrong joint pitch: %+v", pitch)
+	}
+	if extend := robot.Joints["extend"]; *extend.Axis != (quat.Vec3{X: 1}) {
+		t.Errorf("Wrong default axis. Expected (1, 0, 0), got %v", extend.Axis)
+	}
+	if len(robot.Links["shoulder"].Children) != 2 || robot.Links["tool"].Parent.Name != "flange" {
+		t.Errorf("Wrong link tree")
+	}
+}
+
+func TestRobot_Poses(t *testing.T) {
+	robot := readArm(t)
+
+	poses, err := robot.Poses(map[string]float64{"yaw": math.Pi / 2, "pitch": 0.5, "extend": 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The yaw turns the arm towards Y; pitch lifts it in the vertical plane.
+	reach := 1 + 0.2 + 0.1
+	tests := []struct {
+		link string
+		want *quat.Vec3
+	}{
+		{link: "base", want: &quat.Vec3{}},
+		{link: "shoulder", want: &quat.Vec3{Z: 0.5}},
+		{link: "slider", want: &quat.Vec3{Y: 1.2 * math.Cos(0.5), Z: 0.5 + 1.2*math.Sin(0.5)}},
+		{link: "tool", want: &quat.Vec3{Y: reach * math.Cos(0.5), Z: 0.5 + reach*math.Sin(0.5)}},
+		{link: "camera", want: &quat.Vec3{X: -0.2, Z: 0.5}},
+	}
+	for _, tt := range tests {
+		if got := poses[tt.link].Translation(); !got.Equals(tt.want, 1e-12) {
+			t.Errorf("Wrong position of %s. Expected %v, got %v", tt.link, tt.want, got)
+		}
+	}
+
+	if _, err := robot.Poses(map[string]float64{"elbow": 1}); !errors.Is(err, UnknownJointError) {
+		t.Errorf("Expected UnknownJointError, got %v", err)
+	}
+}
+
+func TestFromRPY(t *testing.T) {
+	roll, pitch, yaw := rand.Float64(), rand.Float64(), rand.Float64()
+	q := fromRPY(roll, pitch, yaw)
+
+	// Rz(y)*Ry(p)*Rx(r) written out as a matrix.
+	cr, sr := math.Cos(roll), math.Sin(roll)
+	cp, sp := math.Cos(pitch), math.Sin(pitch)
+	cy, sy := math.Cos(yaw), math.Sin(yaw)
+	want := [3][3]float64{
+		{cy * cp, cy*sp*sr - sy*cr, cy*sp*cr + sy*sr},
+		{sy * cp, sy*sp*sr + cy*cr, sy*sp*cr - cy*sr},
+		{-sp, cp * sr, cp * cr},
+	}
+
+	got := q.Compile().R
+	for r := range want {
+		for c := range want[r] {
+			if math.Abs(got[r][c]-want[r][c]) > 1e-15 {
+				t.Fatalf("Wrong result of fromRPY. Expected %v, got %v", want, got)
+			}
+		}
+	}
+}
+
+func TestRobot_Chain(t *testing.T) {
+	robot := readArm(t)
+
+	chain, names, err := robot.Chain("base", "tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "yaw,pitch,extend" {
+		t.Errorf("Wrong joints of Chain. Expected yaw,pitch,extend, got %v", names)
+	}
+	if j := chain.Joints[2]; !j.Limited || j.Min != 0 || j.Max != 0.5 {
+		t.Errorf("Wrong limits of extend: %+v", j)
+	}
+	if chain.Joints[0].Limited {
+		t.Errorf("Expected continuous joint without limits")
+	}
+
+	q := []float64{rand.Float64(), rand.Float64(), rand.Float64()}
+	end, err := chain.EndEffector(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poses, _ := robot.Poses(map[string]float64{"yaw": q[0], "pitch": q[1], "extend": q[2]})
+	if !end.Translation().Equals(poses["tool"].Translation(), 1e-12) || !end.P.EqualsRotation(poses["tool"].P, 1e-12) {
+		t.Errorf("Wrong result of Chain. Expected %v, got %v", poses["tool"], end)
+	}
+
+	if _, _, err := robot.Chain("tool", "base"); !errors.Is(err, NotConnectedError) {
+		t.Errorf("Expected NotConnectedError, got %v", err)
+	}
+	if _, _, err := robot.Chain("base", "gripper"); !errors.Is(err, UnknownLinkError) {
+		t.Errorf("Expected UnknownLinkError, got %v", err)
+	}
+}
+
+func TestRead_ShouldRejectMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		urdf string
+		want error
+	}{
+		{
+			name: "not xml",
+			urdf: `<robot`,
+			want: MalformedURDFError,
+		},
+		{
+			name: "unknown parent",
+			urdf: `<robot><link name="a"/><joint name="j" type="fixed"><parent link="x"/><child link="a"/></joint></robot>`,
+			want: UnknownLinkError,
+		},
+		{
+			name: "floating joint",
+			urdf: `<robot><link name="a"/><link name="b"/><joint name="j" type="floating"><parent link="a"/><child link="b"/></joint></robot>`,
+			want: UnsupportedJointError,
+		},
+		{
+			name: "two roots",
+			urdf: `<robot><link name="a"/><link name="b"/></robot>`,
+			want: MalformedURDFError,
+		},
+		{
+			name: "cycle",
+			urdf: `<robot><link name="r"/><link name="a"/><link name="b"/>
+				<joint name="ab" type="fixed"><parent link="a"/><child link="b"/></joint>
+				<joint name="ba" type="fixed"><parent link="b"/><child link="a"/></joint></robot>`,
+			want: MalformedURDFError,
+		},
+		{
+			name: "bad origin",
+			urdf: `<robot><link name="a"/><link name="b"/><joint name="j" type="fixed"><parent link="a"/><child link="b"/><origin xyz="1 2"/></joint></robot>`,
+			want: MalformedURDFError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(tt.urdf)); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}